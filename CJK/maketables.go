@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -29,6 +30,7 @@ func main() {
 	output_stroke := flag.Bool("stroke", true, "输出笔顺表")
 	output_reading := flag.Bool("reading", true, "输出读音表")
 	output_radical := flag.Bool("radical", true, "输出部首表")
+	output_radical201 := flag.Bool("radical201", true, "输出《汉字部首表》部首对照表")
 	flag.Parse()
 
 	// 数据文件 Unihan.zip
@@ -45,6 +47,9 @@ func main() {
 	if *output_radical {
 		make_radical_table(*outdir, unihan)
 	}
+	if *output_radical201 {
+		make_radical201_table(*outdir)
+	}
 }
 
 // 读取 Unihan 数据文件
@@ -458,6 +463,160 @@ func MakeRadicalStroke(r rune, radical, stroke int) RadicalStroke {
 	return RadicalStroke(buf) + RadicalStroke(r)
 }
 
+// 《汉字部首表》（GF 0011-2009）主部首
+type Radical201 struct {
+	Origin   rune   // 主部首
+	Variants []rune // 附形部首
+}
+
+const MAX_RADICAL201 = 201
+
+func make_radical201_table(outdir string) {
+	var radicals [MAX_RADICAL201 + 1]Radical201
+	var kangxi, offset [MAX_RADICAL + 1]int
+	chars := make(map[rune]RadicalStroke) // 单独归部的字
+	// 使用手工整理的部首对照表
+	radical_file, err := os.Open("radicals201.txt")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer radical_file.Close()
+	scanner := bufio.NewScanner(radical_file)
+	for i := 1; scanner.Scan(); i++ {
+		if scanner.Err() != nil {
+			log.Fatalln(scanner.Err())
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		var index int
+		// 逐字归部的行：字、主部首编号、除部首笔画数
+		if len(fields) == 3 && len([]rune(fields[0])) == 1 {
+			var stroke int
+			fmt.Sscanf(fields[1], "%d", &index)
+			fmt.Sscanf(fields[2], "%d", &stroke)
+			if index < 1 || index > MAX_RADICAL201 {
+				log.Fatalf("部首对照表第 %d 行部首编号错误。\n", i)
+			}
+			r := []rune(fields[0])[0]
+			chars[r] = MakeRadicalStroke(r, index, stroke)
+			continue
+		}
+		if len(fields) != 4 || len([]rune(fields[1])) != 1 {
+			log.Fatalf("部首对照表第 %d 行语法错误。\n", i)
+		}
+		fmt.Sscanf(fields[0], "%d", &index)
+		if index < 1 || index > MAX_RADICAL201 || radicals[index].Origin != 0 {
+			log.Fatalf("部首对照表第 %d 行部首编号错误。\n", i)
+		}
+		radicals[index].Origin = []rune(fields[1])[0]
+		if fields[2] != "-" {
+			for _, v := range strings.Split(fields[2], ",") {
+				radicals[index].Variants = append(radicals[index].Variants, []rune(v)...)
+			}
+		}
+		if fields[3] == "-" {
+			continue
+		}
+		for _, k := range strings.Split(fields[3], ",") {
+			// 形如 7+1，加号后是康熙字典部首与主部首的笔画数之差
+			var kindex, koffset int
+			if plus := strings.Index(k, "+"); plus >= 0 {
+				fmt.Sscanf(k[plus+1:], "%d", &koffset)
+				k = k[:plus]
+			}
+			fmt.Sscanf(k, "%d", &kindex)
+			if kindex < 1 || kindex > MAX_RADICAL || kangxi[kindex] != 0 {
+				log.Fatalf("部首对照表第 %d 行康熙字典部首编号错误。\n", i)
+			}
+			kangxi[kindex] = index
+			offset[kindex] = koffset
+		}
+	}
+	for i := 1; i < MAX_RADICAL+1; i++ {
+		if kangxi[i] == 0 {
+			log.Fatalf("康熙字典部首 %d 没有对应的主部首。\n", i)
+		}
+	}
+	// 部首字本身归入该部首，除部首笔画数为 0
+	for i := 1; i < MAX_RADICAL201+1; i++ {
+		for _, r := range append([]rune{radicals[i].Origin}, radicals[i].Variants...) {
+			if _, ok := chars[r]; !ok {
+				chars[r] = MakeRadicalStroke(r, i, 0)
+			}
+		}
+	}
+	// 输出
+	outfile, err := os.Create(path.Join(outdir, "radicals201.go"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer outfile.Close()
+	fmt.Fprintln(outfile, `// 这是由程序自动生成的文件，请不要直接编辑此文件
+// 部首来源：radicals201.txt
+
+package CJK
+
+// Radical201 是《汉字部首表》（GF 0011-2009）的主部首类型。
+type Radical201 struct {
+	Origin   rune   // 主部首
+	Variants string // 附形部首
+}
+
+const MAX_RADICAL201 = 201
+
+// Radicals201 是所有主部首。
+var Radicals201 [MAX_RADICAL201 + 1]Radical201 = radicals201
+
+var radicals201 = [MAX_RADICAL201 + 1]Radical201{`)
+	for i := 1; i < MAX_RADICAL201+1; i++ {
+		fmt.Fprintf(outfile, "\t%d: {%#x, %+q}, // %c",
+			i, radicals[i].Origin, string(radicals[i].Variants), radicals[i].Origin)
+		if len(radicals[i].Variants) == 0 {
+			fmt.Fprintln(outfile)
+		} else {
+			fmt.Fprintf(outfile, " (%s)\n", string(radicals[i].Variants))
+		}
+	}
+	fmt.Fprintln(outfile, "}\n")
+	fmt.Fprintln(outfile, `// KangxiToRadical201 从康熙字典部首编号取得对应的主部首编号。
+var KangxiToRadical201 [MAX_RADICAL + 1]int = kangxiToRadical201
+
+var kangxiToRadical201 = [MAX_RADICAL + 1]int{`)
+	for i := 1; i < MAX_RADICAL+1; i++ {
+		fmt.Fprintf(outfile, "\t%d: %d, // %c\n", i, kangxi[i], radicals[kangxi[i]].Origin)
+	}
+	fmt.Fprintln(outfile, "}\n")
+	fmt.Fprintln(outfile, `// KangxiStrokeOffset201 从康熙字典部首编号取得归入主部首后除部首笔画数的增量，
+// 即康熙字典部首与主部首的笔画数之差。
+var KangxiStrokeOffset201 [MAX_RADICAL + 1]int = kangxiStrokeOffset201
+
+var kangxiStrokeOffset201 = [MAX_RADICAL + 1]int{`)
+	for i := 1; i < MAX_RADICAL+1; i++ {
+		if offset[i] != 0 {
+			fmt.Fprintf(outfile, "\t%d: %d, // %c\n", i, offset[i], radicals[kangxi[i]].Origin)
+		}
+	}
+	fmt.Fprintln(outfile, "}\n")
+	fmt.Fprintln(outfile, `// CharRadicals201 是单独归部的字的主部首与除部首笔画数，包括各部首字本身与对照表中
+// 逐字列出的字；其他字按康熙字典部首整部对照。
+var CharRadicals201 map[rune]RadicalStroke = charRadicals201
+
+var charRadicals201 = map[rune]RadicalStroke{`)
+	var runes []rune
+	for r := range chars {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	for _, r := range runes {
+		rs := chars[r]
+		fmt.Fprintf(outfile, "\t%#x: \"\\x%02x\\x%02x%c\", // %c\n", r, rs[0], rs[1], r, r)
+	}
+	fmt.Fprintln(outfile, "}")
+}
+
 // 读取 Unihan_IRGSources.txt 获取部首笔画数表
 func read_radical_strokes(unihan *zip.Reader) (version string, CJKRadicalStrokes []RadicalStroke) {
 	radical_file := getUnihanFile(unihan, "Unihan_IRGSources.txt")
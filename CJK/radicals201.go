@@ -0,0 +1,696 @@
+// 这是由程序自动生成的文件，请不要直接编辑此文件
+// 部首来源：radicals201.txt
+
+package CJK
+
+// Radical201 是《汉字部首表》（GF 0011-2009）的主部首类型。
+type Radical201 struct {
+	Origin   rune   // 主部首
+	Variants string // 附形部首
+}
+
+const MAX_RADICAL201 = 201
+
+// Radicals201 是所有主部首。
+var Radicals201 [MAX_RADICAL201 + 1]Radical201 = radicals201
+
+var radicals201 = [MAX_RADICAL201 + 1]Radical201{
+	1: {0x4e00, ""}, // 一
+	2: {0x4e28, ""}, // 丨
+	3: {0x4e3f, ""}, // 丿
+	4: {0x4e36, ""}, // 丶
+	5: {0x4e59, "\u4e5b\u4e5a"}, // 乙 (乛乚)
+	6: {0x5341, ""}, // 十
+	7: {0x5382, ""}, // 厂
+	8: {0x531a, ""}, // 匚
+	9: {0x535c, ""}, // 卜
+	10: {0x5182, ""}, // 冂
+	11: {0x516b, "\u4e37"}, // 八 (丷)
+	12: {0x4eba, "\u4ebb\u5165"}, // 人 (亻入)
+	13: {0x52f9, ""}, // 勹
+	14: {0x513f, ""}, // 儿
+	15: {0x5315, ""}, // 匕
+	16: {0x51e0, ""}, // 几
+	17: {0x4ea0, ""}, // 亠
+	18: {0x51ab, ""}, // 冫
+	19: {0x5196, ""}, // 冖
+	20: {0x51f5, ""}, // 凵
+	21: {0x5369, "\u353e"}, // 卩 (㔾)
+	22: {0x5200, "\u5202"}, // 刀 (刂)
+	23: {0x529b, ""}, // 力
+	24: {0x53c8, ""}, // 又
+	25: {0x53b6, ""}, // 厶
+	26: {0x5ef4, ""}, // 廴
+	27: {0x5e72, ""}, // 干
+	28: {0x5de5, ""}, // 工
+	29: {0x571f, "\u58eb"}, // 土 (士)
+	30: {0x8279, "\u8278"}, // 艹 (艸)
+	31: {0x5bf8, ""}, // 寸
+	32: {0x5efe, ""}, // 廾
+	33: {0x5927, ""}, // 大
+	34: {0x5c22, ""}, // 尢
+	35: {0x5f0b, ""}, // 弋
+	36: {0x5c0f, "\u2e8c"}, // 小 (⺌)
+	37: {0x53e3, ""}, // 口
+	38: {0x56d7, ""}, // 囗
+	39: {0x5c71, ""}, // 山
+	40: {0x5dfe, ""}, // 巾
+	41: {0x5f73, ""}, // 彳
+	42: {0x5f61, ""}, // 彡
+	43: {0x5915, ""}, // 夕
+	44: {0x5902, ""}, // 夂
+	45: {0x4e2c, ""}, // 丬
+	46: {0x5e7f, ""}, // 广
+	47: {0x95e8, ""}, // 门
+	48: {0x5b80, ""}, // 宀
+	49: {0x8fb6, ""}, // 辶
+	50: {0x5f50, "\u2e95"}, // 彐 (⺕)
+	51: {0x5c38, ""}, // 尸
+	52: {0x5df1, "\u5df2\u5df3"}, // 己 (已巳)
+	53: {0x5f13, ""}, // 弓
+	54: {0x5b50, ""}, // 子
+	55: {0x5c6e, ""}, // 屮
+	56: {0x5973, ""}, // 女
+	57: {0x98de, ""}, // 飞
+	58: {0x9a6c, ""}, // 马
+	59: {0x5e7a, ""}, // 幺
+	60: {0x5ddb, ""}, // 巛
+	61: {0x738b, "\u7389"}, // 王 (玉)
+	62: {0x65e0, ""}, // 无
+	63: {0x97e6, ""}, // 韦
+	64: {0x6728, ""}, // 木
+	65: {0x652f, ""}, // 支
+	66: {0x72ac, "\u72ad"}, // 犬 (犭)
+	67: {0x6b79, ""}, // 歹
+	68: {0x8f66, ""}, // 车
+	69: {0x7259, ""}, // 牙
+	70: {0x6208, ""}, // 戈
+	71: {0x6bd4, ""}, // 比
+	72: {0x74e6, ""}, // 瓦
+	73: {0x6b62, ""}, // 止
+	74: {0x6534, "\u6535"}, // 攴 (攵)
+	75: {0x65e5, "\u66f0"}, // 日 (曰)
+	76: {0x8d1d, ""}, // 贝
+	77: {0x6c34, "\u6c35"}, // 水 (氵)
+	78: {0x89c1, ""}, // 见
+	79: {0x725b, "\u725c"}, // 牛 (牜)
+	80: {0x624b, "\u624c"}, // 手 (扌)
+	81: {0x6c14, ""}, // 气
+	82: {0x6bdb, ""}, // 毛
+	83: {0x957f, ""}, // 长
+	84: {0x7247, ""}, // 片
+	85: {0x65a4, ""}, // 斤
+	86: {0x722a, "\u722b"}, // 爪 (爫)
+	87: {0x7236, ""}, // 父
+	88: {0x6708, ""}, // 月
+	89: {0x6c0f, ""}, // 氏
+	90: {0x6b20, ""}, // 欠
+	91: {0x98ce, ""}, // 风
+	92: {0x6bb3, ""}, // 殳
+	93: {0x6587, ""}, // 文
+	94: {0x65b9, ""}, // 方
+	95: {0x706b, "\u706c"}, // 火 (灬)
+	96: {0x6597, ""}, // 斗
+	97: {0x6237, ""}, // 户
+	98: {0x5fc3, "\u5fc4"}, // 心 (忄)
+	99: {0x6bcb, "\u6bcd"}, // 毋 (母)
+	100: {0x793a, "\u793b"}, // 示 (礻)
+	101: {0x7518, ""}, // 甘
+	102: {0x77f3, ""}, // 石
+	103: {0x9f99, ""}, // 龙
+	104: {0x4e1a, ""}, // 业
+	105: {0x76ee, ""}, // 目
+	106: {0x7530, ""}, // 田
+	107: {0x7f52, "\u7f51"}, // 罒 (网)
+	108: {0x76bf, ""}, // 皿
+	109: {0x751f, ""}, // 生
+	110: {0x77e2, ""}, // 矢
+	111: {0x79be, ""}, // 禾
+	112: {0x767d, ""}, // 白
+	113: {0x74dc, ""}, // 瓜
+	114: {0x9e1f, ""}, // 鸟
+	115: {0x7592, ""}, // 疒
+	116: {0x7acb, ""}, // 立
+	117: {0x7a74, ""}, // 穴
+	118: {0x758b, ""}, // 疋
+	119: {0x76ae, ""}, // 皮
+	120: {0x7676, ""}, // 癶
+	121: {0x77db, ""}, // 矛
+	122: {0x8012, ""}, // 耒
+	123: {0x8001, ""}, // 老
+	124: {0x8033, ""}, // 耳
+	125: {0x81e3, ""}, // 臣
+	126: {0x897f, "\u897e"}, // 西 (襾)
+	127: {0x800c, ""}, // 而
+	128: {0x9875, ""}, // 页
+	129: {0x81f3, ""}, // 至
+	130: {0x864d, ""}, // 虍
+	131: {0x866b, ""}, // 虫
+	132: {0x8089, ""}, // 肉
+	133: {0x7f36, ""}, // 缶
+	134: {0x820c, ""}, // 舌
+	135: {0x7af9, "\u2eae"}, // 竹 (⺮)
+	136: {0x81fc, ""}, // 臼
+	137: {0x81ea, ""}, // 自
+	138: {0x8840, ""}, // 血
+	139: {0x821f, ""}, // 舟
+	140: {0x8272, ""}, // 色
+	141: {0x9f50, ""}, // 齐
+	142: {0x8863, "\u8864"}, // 衣 (衤)
+	143: {0x7f8a, ""}, // 羊
+	144: {0x7c73, ""}, // 米
+	145: {0x807f, ""}, // 聿
+	146: {0x826e, ""}, // 艮
+	147: {0x7fbd, ""}, // 羽
+	148: {0x7cf8, "\u7e9f"}, // 糸 (纟)
+	149: {0x9ea6, ""}, // 麦
+	150: {0x8d70, ""}, // 走
+	151: {0x8d64, ""}, // 赤
+	152: {0x8c46, ""}, // 豆
+	153: {0x9149, ""}, // 酉
+	154: {0x8fb0, ""}, // 辰
+	155: {0x8c55, ""}, // 豕
+	156: {0x5364, ""}, // 卤
+	157: {0x91cc, ""}, // 里
+	158: {0x8db3, ""}, // 足
+	159: {0x9091, "\u961d"}, // 邑 (阝)
+	160: {0x8eab, ""}, // 身
+	161: {0x91c6, ""}, // 釆
+	162: {0x8c37, ""}, // 谷
+	163: {0x8c78, ""}, // 豸
+	164: {0x9f9f, ""}, // 龟
+	165: {0x89d2, ""}, // 角
+	166: {0x8a00, "\u8ba0"}, // 言 (讠)
+	167: {0x8f9b, ""}, // 辛
+	168: {0x9752, ""}, // 青
+	169: {0x2099d, ""}, // 𠦝
+	170: {0x96e8, ""}, // 雨
+	171: {0x975e, ""}, // 非
+	172: {0x9f7f, ""}, // 齿
+	173: {0x9efe, ""}, // 黾
+	174: {0x96b9, ""}, // 隹
+	175: {0x961c, "\u961d"}, // 阜 (阝)
+	176: {0x91d1, "\u9485"}, // 金 (钅)
+	177: {0x9c7c, ""}, // 鱼
+	178: {0x96b6, ""}, // 隶
+	179: {0x9769, ""}, // 革
+	180: {0x9762, ""}, // 面
+	181: {0x97ed, ""}, // 韭
+	182: {0x9aa8, ""}, // 骨
+	183: {0x9999, ""}, // 香
+	184: {0x9b3c, ""}, // 鬼
+	185: {0x98df, "\u9963"}, // 食 (饣)
+	186: {0x97f3, ""}, // 音
+	187: {0x9996, ""}, // 首
+	188: {0x9adf, ""}, // 髟
+	189: {0x9b32, ""}, // 鬲
+	190: {0x9b25, ""}, // 鬥
+	191: {0x9ad8, ""}, // 高
+	192: {0x9ec4, ""}, // 黄
+	193: {0x9ebb, ""}, // 麻
+	194: {0x9e7f, ""}, // 鹿
+	195: {0x9f0e, ""}, // 鼎
+	196: {0x9ed1, ""}, // 黑
+	197: {0x9ecd, ""}, // 黍
+	198: {0x9f13, ""}, // 鼓
+	199: {0x9f20, ""}, // 鼠
+	200: {0x9f3b, ""}, // 鼻
+	201: {0x9fa0, ""}, // 龠
+}
+
+// KangxiToRadical201 从康熙字典部首编号取得对应的主部首编号。
+var KangxiToRadical201 [MAX_RADICAL + 1]int = kangxiToRadical201
+
+var kangxiToRadical201 = [MAX_RADICAL + 1]int{
+	1: 1, // 一
+	2: 2, // 丨
+	3: 4, // 丶
+	4: 3, // 丿
+	5: 5, // 乙
+	6: 2, // 丨
+	7: 1, // 一
+	8: 17, // 亠
+	9: 12, // 人
+	10: 14, // 儿
+	11: 12, // 人
+	12: 11, // 八
+	13: 10, // 冂
+	14: 19, // 冖
+	15: 18, // 冫
+	16: 16, // 几
+	17: 20, // 凵
+	18: 22, // 刀
+	19: 23, // 力
+	20: 13, // 勹
+	21: 15, // 匕
+	22: 8, // 匚
+	23: 8, // 匚
+	24: 6, // 十
+	25: 9, // 卜
+	26: 21, // 卩
+	27: 7, // 厂
+	28: 25, // 厶
+	29: 24, // 又
+	30: 37, // 口
+	31: 38, // 囗
+	32: 29, // 土
+	33: 29, // 土
+	34: 44, // 夂
+	35: 44, // 夂
+	36: 43, // 夕
+	37: 33, // 大
+	38: 56, // 女
+	39: 54, // 子
+	40: 48, // 宀
+	41: 31, // 寸
+	42: 36, // 小
+	43: 34, // 尢
+	44: 51, // 尸
+	45: 55, // 屮
+	46: 39, // 山
+	47: 60, // 巛
+	48: 28, // 工
+	49: 52, // 己
+	50: 40, // 巾
+	51: 27, // 干
+	52: 59, // 幺
+	53: 46, // 广
+	54: 26, // 廴
+	55: 32, // 廾
+	56: 35, // 弋
+	57: 53, // 弓
+	58: 50, // 彐
+	59: 42, // 彡
+	60: 41, // 彳
+	61: 98, // 心
+	62: 70, // 戈
+	63: 97, // 户
+	64: 80, // 手
+	65: 65, // 支
+	66: 74, // 攴
+	67: 93, // 文
+	68: 96, // 斗
+	69: 85, // 斤
+	70: 94, // 方
+	71: 62, // 无
+	72: 75, // 日
+	73: 75, // 日
+	74: 88, // 月
+	75: 64, // 木
+	76: 90, // 欠
+	77: 73, // 止
+	78: 67, // 歹
+	79: 92, // 殳
+	80: 99, // 毋
+	81: 71, // 比
+	82: 82, // 毛
+	83: 89, // 氏
+	84: 81, // 气
+	85: 77, // 水
+	86: 95, // 火
+	87: 86, // 爪
+	88: 87, // 父
+	89: 87, // 父
+	90: 45, // 丬
+	91: 84, // 片
+	92: 69, // 牙
+	93: 79, // 牛
+	94: 66, // 犬
+	95: 17, // 亠
+	96: 61, // 王
+	97: 113, // 瓜
+	98: 72, // 瓦
+	99: 101, // 甘
+	100: 109, // 生
+	101: 10, // 冂
+	102: 106, // 田
+	103: 118, // 疋
+	104: 115, // 疒
+	105: 120, // 癶
+	106: 112, // 白
+	107: 119, // 皮
+	108: 108, // 皿
+	109: 105, // 目
+	110: 121, // 矛
+	111: 110, // 矢
+	112: 102, // 石
+	113: 100, // 示
+	114: 10, // 冂
+	115: 111, // 禾
+	116: 117, // 穴
+	117: 116, // 立
+	118: 135, // 竹
+	119: 144, // 米
+	120: 148, // 糸
+	121: 133, // 缶
+	122: 107, // 罒
+	123: 143, // 羊
+	124: 147, // 羽
+	125: 123, // 老
+	126: 127, // 而
+	127: 122, // 耒
+	128: 124, // 耳
+	129: 145, // 聿
+	130: 132, // 肉
+	131: 125, // 臣
+	132: 137, // 自
+	133: 129, // 至
+	134: 136, // 臼
+	135: 134, // 舌
+	136: 43, // 夕
+	137: 139, // 舟
+	138: 146, // 艮
+	139: 140, // 色
+	140: 30, // 艹
+	141: 130, // 虍
+	142: 131, // 虫
+	143: 138, // 血
+	144: 41, // 彳
+	145: 142, // 衣
+	146: 126, // 西
+	147: 78, // 见
+	148: 165, // 角
+	149: 166, // 言
+	150: 162, // 谷
+	151: 152, // 豆
+	152: 155, // 豕
+	153: 163, // 豸
+	154: 76, // 贝
+	155: 151, // 赤
+	156: 150, // 走
+	157: 158, // 足
+	158: 160, // 身
+	159: 68, // 车
+	160: 167, // 辛
+	161: 154, // 辰
+	162: 49, // 辶
+	163: 159, // 邑
+	164: 153, // 酉
+	165: 161, // 釆
+	166: 157, // 里
+	167: 176, // 金
+	168: 83, // 长
+	169: 47, // 门
+	170: 175, // 阜
+	171: 178, // 隶
+	172: 174, // 隹
+	173: 170, // 雨
+	174: 168, // 青
+	175: 171, // 非
+	176: 180, // 面
+	177: 179, // 革
+	178: 63, // 韦
+	179: 181, // 韭
+	180: 186, // 音
+	181: 128, // 页
+	182: 91, // 风
+	183: 57, // 飞
+	184: 185, // 食
+	185: 187, // 首
+	186: 183, // 香
+	187: 58, // 马
+	188: 182, // 骨
+	189: 191, // 高
+	190: 188, // 髟
+	191: 190, // 鬥
+	192: 15, // 匕
+	193: 189, // 鬲
+	194: 184, // 鬼
+	195: 177, // 鱼
+	196: 114, // 鸟
+	197: 156, // 卤
+	198: 194, // 鹿
+	199: 149, // 麦
+	200: 193, // 麻
+	201: 192, // 黄
+	202: 197, // 黍
+	203: 196, // 黑
+	204: 104, // 业
+	205: 173, // 黾
+	206: 195, // 鼎
+	207: 198, // 鼓
+	208: 199, // 鼠
+	209: 200, // 鼻
+	210: 141, // 齐
+	211: 172, // 齿
+	212: 103, // 龙
+	213: 164, // 龟
+	214: 201, // 龠
+}
+
+// KangxiStrokeOffset201 从康熙字典部首编号取得归入主部首后除部首笔画数的增量，
+// 即康熙字典部首与主部首的笔画数之差。
+var KangxiStrokeOffset201 [MAX_RADICAL + 1]int = kangxiStrokeOffset201
+
+var kangxiStrokeOffset201 = [MAX_RADICAL + 1]int{
+	7: 1, // 一
+	95: 3, // 亠
+	101: 3, // 冂
+	114: 3, // 冂
+	136: 3, // 夕
+	144: 3, // 彳
+	192: 8, // 匕
+	204: 7, // 业
+}
+
+// CharRadicals201 是单独归部的字的主部首与除部首笔画数，包括各部首字本身与对照表中
+// 逐字列出的字；其他字按康熙字典部首整部对照。
+var CharRadicals201 map[rune]RadicalStroke = charRadicals201
+
+var charRadicals201 = map[rune]RadicalStroke{
+	0x2e8c: "\x24\x00⺌", // ⺌
+	0x2e95: "\x32\x00⺕", // ⺕
+	0x2eae: "\x87\x00⺮", // ⺮
+	0x353e: "\x15\x00㔾", // 㔾
+	0x4e00: "\x01\x00一", // 一
+	0x4e1a: "\x68\x00业", // 业
+	0x4e28: "\x02\x00丨", // 丨
+	0x4e2c: "\x2d\x00丬", // 丬
+	0x4e36: "\x04\x00丶", // 丶
+	0x4e37: "\x0b\x00丷", // 丷
+	0x4e3f: "\x03\x00丿", // 丿
+	0x4e59: "\x05\x00乙", // 乙
+	0x4e5a: "\x05\x00乚", // 乚
+	0x4e5b: "\x05\x00乛", // 乛
+	0x4ea0: "\x11\x00亠", // 亠
+	0x4eba: "\x0c\x00人", // 人
+	0x4ebb: "\x0c\x00亻", // 亻
+	0x513f: "\x0e\x00儿", // 儿
+	0x5165: "\x0c\x00入", // 入
+	0x516b: "\x0b\x00八", // 八
+	0x5182: "\x0a\x00冂", // 冂
+	0x5196: "\x13\x00冖", // 冖
+	0x51ab: "\x12\x00冫", // 冫
+	0x51e0: "\x10\x00几", // 几
+	0x51f5: "\x14\x00凵", // 凵
+	0x5200: "\x16\x00刀", // 刀
+	0x5202: "\x16\x00刂", // 刂
+	0x529b: "\x17\x00力", // 力
+	0x52f9: "\x0d\x00勹", // 勹
+	0x5315: "\x0f\x00匕", // 匕
+	0x531a: "\x08\x00匚", // 匚
+	0x5341: "\x06\x00十", // 十
+	0x535c: "\x09\x00卜", // 卜
+	0x5364: "\x9c\x00卤", // 卤
+	0x5369: "\x15\x00卩", // 卩
+	0x5382: "\x07\x00厂", // 厂
+	0x53b6: "\x19\x00厶", // 厶
+	0x53c8: "\x18\x00又", // 又
+	0x53e3: "\x25\x00口", // 口
+	0x56d7: "\x26\x00囗", // 囗
+	0x571f: "\x1d\x00土", // 土
+	0x58eb: "\x1d\x00士", // 士
+	0x5902: "\x2c\x00夂", // 夂
+	0x5915: "\x2b\x00夕", // 夕
+	0x5927: "\x21\x00大", // 大
+	0x5973: "\x38\x00女", // 女
+	0x5b50: "\x36\x00子", // 子
+	0x5b80: "\x30\x00宀", // 宀
+	0x5bf8: "\x1f\x00寸", // 寸
+	0x5c0f: "\x24\x00小", // 小
+	0x5c22: "\x22\x00尢", // 尢
+	0x5c38: "\x33\x00尸", // 尸
+	0x5c6e: "\x37\x00屮", // 屮
+	0x5c71: "\x27\x00山", // 山
+	0x5ddb: "\x3c\x00巛", // 巛
+	0x5de5: "\x1c\x00工", // 工
+	0x5df1: "\x34\x00己", // 己
+	0x5df2: "\x34\x00已", // 已
+	0x5df3: "\x34\x00巳", // 巳
+	0x5dfe: "\x28\x00巾", // 巾
+	0x5e72: "\x1b\x00干", // 干
+	0x5e7a: "\x3b\x00幺", // 幺
+	0x5e7f: "\x2e\x00广", // 广
+	0x5ef4: "\x1a\x00廴", // 廴
+	0x5efe: "\x20\x00廾", // 廾
+	0x5f0b: "\x23\x00弋", // 弋
+	0x5f13: "\x35\x00弓", // 弓
+	0x5f50: "\x32\x00彐", // 彐
+	0x5f61: "\x2a\x00彡", // 彡
+	0x5f73: "\x29\x00彳", // 彳
+	0x5fc3: "\x62\x00心", // 心
+	0x5fc4: "\x62\x00忄", // 忄
+	0x6208: "\x46\x00戈", // 戈
+	0x6237: "\x61\x00户", // 户
+	0x624b: "\x50\x00手", // 手
+	0x624c: "\x50\x00扌", // 扌
+	0x652f: "\x41\x00支", // 支
+	0x6534: "\x4a\x00攴", // 攴
+	0x6535: "\x4a\x00攵", // 攵
+	0x6587: "\x5d\x00文", // 文
+	0x6597: "\x60\x00斗", // 斗
+	0x65a4: "\x55\x00斤", // 斤
+	0x65b9: "\x5e\x00方", // 方
+	0x65e0: "\x3e\x00无", // 无
+	0x65e5: "\x4b\x00日", // 日
+	0x66f0: "\x4b\x00曰", // 曰
+	0x6708: "\x58\x00月", // 月
+	0x6728: "\x40\x00木", // 木
+	0x6b20: "\x5a\x00欠", // 欠
+	0x6b62: "\x49\x00止", // 止
+	0x6b79: "\x43\x00歹", // 歹
+	0x6bb3: "\x5c\x00殳", // 殳
+	0x6bcb: "\x63\x00毋", // 毋
+	0x6bcd: "\x63\x00母", // 母
+	0x6bd4: "\x47\x00比", // 比
+	0x6bdb: "\x52\x00毛", // 毛
+	0x6c0f: "\x59\x00氏", // 氏
+	0x6c14: "\x51\x00气", // 气
+	0x6c34: "\x4d\x00水", // 水
+	0x6c35: "\x4d\x00氵", // 氵
+	0x706b: "\x5f\x00火", // 火
+	0x706c: "\x5f\x00灬", // 灬
+	0x722a: "\x56\x00爪", // 爪
+	0x722b: "\x56\x00爫", // 爫
+	0x7236: "\x57\x00父", // 父
+	0x7247: "\x54\x00片", // 片
+	0x7259: "\x45\x00牙", // 牙
+	0x725b: "\x4f\x00牛", // 牛
+	0x725c: "\x4f\x00牜", // 牜
+	0x72ac: "\x42\x00犬", // 犬
+	0x72ad: "\x42\x00犭", // 犭
+	0x7389: "\x3d\x00玉", // 玉
+	0x738b: "\x3d\x00王", // 王
+	0x74dc: "\x71\x00瓜", // 瓜
+	0x74e6: "\x48\x00瓦", // 瓦
+	0x7518: "\x65\x00甘", // 甘
+	0x751f: "\x6d\x00生", // 生
+	0x7530: "\x6a\x00田", // 田
+	0x758b: "\x76\x00疋", // 疋
+	0x7592: "\x73\x00疒", // 疒
+	0x7676: "\x78\x00癶", // 癶
+	0x767d: "\x70\x00白", // 白
+	0x76ae: "\x77\x00皮", // 皮
+	0x76bf: "\x6c\x00皿", // 皿
+	0x76ee: "\x69\x00目", // 目
+	0x77db: "\x79\x00矛", // 矛
+	0x77e2: "\x6e\x00矢", // 矢
+	0x77f3: "\x66\x00石", // 石
+	0x793a: "\x64\x00示", // 示
+	0x793b: "\x64\x00礻", // 礻
+	0x79be: "\x6f\x00禾", // 禾
+	0x7a74: "\x75\x00穴", // 穴
+	0x7acb: "\x74\x00立", // 立
+	0x7af9: "\x87\x00竹", // 竹
+	0x7c73: "\x90\x00米", // 米
+	0x7cf8: "\x94\x00糸", // 糸
+	0x7e9f: "\x94\x00纟", // 纟
+	0x7f36: "\x85\x00缶", // 缶
+	0x7f51: "\x6b\x00网", // 网
+	0x7f52: "\x6b\x00罒", // 罒
+	0x7f8a: "\x8f\x00羊", // 羊
+	0x7fbd: "\x93\x00羽", // 羽
+	0x8001: "\x7b\x00老", // 老
+	0x800c: "\x7f\x00而", // 而
+	0x8012: "\x7a\x00耒", // 耒
+	0x8033: "\x7c\x00耳", // 耳
+	0x807f: "\x91\x00聿", // 聿
+	0x8089: "\x84\x00肉", // 肉
+	0x81e3: "\x7d\x00臣", // 臣
+	0x81ea: "\x89\x00自", // 自
+	0x81f3: "\x81\x00至", // 至
+	0x81fc: "\x88\x00臼", // 臼
+	0x820c: "\x86\x00舌", // 舌
+	0x821f: "\x8b\x00舟", // 舟
+	0x826e: "\x92\x00艮", // 艮
+	0x8272: "\x8c\x00色", // 色
+	0x8278: "\x1e\x00艸", // 艸
+	0x8279: "\x1e\x00艹", // 艹
+	0x864d: "\x82\x00虍", // 虍
+	0x866b: "\x83\x00虫", // 虫
+	0x8840: "\x8a\x00血", // 血
+	0x8863: "\x8e\x00衣", // 衣
+	0x8864: "\x8e\x00衤", // 衤
+	0x897e: "\x7e\x00襾", // 襾
+	0x897f: "\x7e\x00西", // 西
+	0x89c1: "\x4e\x00见", // 见
+	0x89d2: "\xa5\x00角", // 角
+	0x8a00: "\xa6\x00言", // 言
+	0x8ba0: "\xa6\x00讠", // 讠
+	0x8c37: "\xa2\x00谷", // 谷
+	0x8c46: "\x98\x00豆", // 豆
+	0x8c55: "\x9b\x00豕", // 豕
+	0x8c78: "\xa3\x00豸", // 豸
+	0x8d1d: "\x4c\x00贝", // 贝
+	0x8d64: "\x97\x00赤", // 赤
+	0x8d70: "\x96\x00走", // 走
+	0x8db3: "\x9e\x00足", // 足
+	0x8eab: "\xa0\x00身", // 身
+	0x8f66: "\x44\x00车", // 车
+	0x8f9b: "\xa7\x00辛", // 辛
+	0x8fb0: "\x9a\x00辰", // 辰
+	0x8fb6: "\x31\x00辶", // 辶
+	0x9091: "\x9f\x00邑", // 邑
+	0x9149: "\x99\x00酉", // 酉
+	0x91c6: "\xa1\x00釆", // 釆
+	0x91cc: "\x9d\x00里", // 里
+	0x91d1: "\xb0\x00金", // 金
+	0x9485: "\xb0\x00钅", // 钅
+	0x957f: "\x53\x00长", // 长
+	0x95e8: "\x2f\x00门", // 门
+	0x961c: "\xaf\x00阜", // 阜
+	0x961d: "\x9f\x00阝", // 阝
+	0x96b6: "\xb2\x00隶", // 隶
+	0x96b9: "\xae\x00隹", // 隹
+	0x96e8: "\xaa\x00雨", // 雨
+	0x9752: "\xa8\x00青", // 青
+	0x975e: "\xab\x00非", // 非
+	0x9762: "\xb4\x00面", // 面
+	0x9769: "\xb3\x00革", // 革
+	0x97e6: "\x3f\x00韦", // 韦
+	0x97ed: "\xb5\x00韭", // 韭
+	0x97f3: "\xba\x00音", // 音
+	0x9875: "\x80\x00页", // 页
+	0x98ce: "\x5b\x00风", // 风
+	0x98de: "\x39\x00飞", // 飞
+	0x98df: "\xb9\x00食", // 食
+	0x9963: "\xb9\x00饣", // 饣
+	0x9996: "\xbb\x00首", // 首
+	0x9999: "\xb7\x00香", // 香
+	0x9a6c: "\x3a\x00马", // 马
+	0x9aa8: "\xb6\x00骨", // 骨
+	0x9ad8: "\xbf\x00高", // 高
+	0x9adf: "\xbc\x00髟", // 髟
+	0x9b25: "\xbe\x00鬥", // 鬥
+	0x9b32: "\xbd\x00鬲", // 鬲
+	0x9b3c: "\xb8\x00鬼", // 鬼
+	0x9c7c: "\xb1\x00鱼", // 鱼
+	0x9e1f: "\x72\x00鸟", // 鸟
+	0x9e7f: "\xc2\x00鹿", // 鹿
+	0x9ea6: "\x95\x00麦", // 麦
+	0x9ebb: "\xc1\x00麻", // 麻
+	0x9ec4: "\xc0\x00黄", // 黄
+	0x9ecd: "\xc5\x00黍", // 黍
+	0x9ed1: "\xc4\x00黑", // 黑
+	0x9efe: "\xad\x00黾", // 黾
+	0x9f0e: "\xc3\x00鼎", // 鼎
+	0x9f13: "\xc6\x00鼓", // 鼓
+	0x9f20: "\xc7\x00鼠", // 鼠
+	0x9f3b: "\xc8\x00鼻", // 鼻
+	0x9f50: "\x8d\x00齐", // 齐
+	0x9f7f: "\xac\x00齿", // 齿
+	0x9f99: "\x67\x00龙", // 龙
+	0x9f9f: "\xa4\x00龟", // 龟
+	0x9fa0: "\xc9\x00龠", // 龠
+	0x2099d: "\xa9\x00𠦝", // 𠦝
+}
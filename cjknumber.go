@@ -0,0 +1,218 @@
+package main
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// 汉字数字的值，包括小写数字、大写（财务）数字与繁体字形
+var cjkDigitValue = map[rune]int{
+	'〇': 0, '零': 0,
+	'一': 1, '壹': 1,
+	'二': 2, '贰': 2, '貳': 2, '两': 2, '兩': 2,
+	'三': 3, '叁': 3, '參': 3,
+	'四': 4, '肆': 4,
+	'五': 5, '伍': 5,
+	'六': 6, '陆': 6, '陸': 6,
+	'七': 7, '柒': 7,
+	'八': 8, '捌': 8,
+	'九': 9, '玖': 9,
+}
+
+// 汉字数字的单位
+var cjkUnitValue = map[rune]uint64{
+	'十': 10, '拾': 10,
+	'百': 100, '佰': 100,
+	'千': 1000, '仟': 1000,
+	'万': 10000, '萬': 10000,
+	'亿': 100000000, '億': 100000000,
+}
+
+// 测试是否是汉字数字或单位
+func IsCJKNumRune(r rune) bool {
+	_, isDigit := cjkDigitValue[r]
+	return isDigit || cjkUnitValue[r] != 0
+}
+
+// 测试是否整个串都是汉字数字
+func IsCJKNumString(s string) bool {
+	token := []rune(s)
+	_, n := scanCJKNumber(token)
+	return n > 0 && n == len(token)
+}
+
+// 从 token 开头读入汉字数字，返回数值与读入的字符数；不是汉字数字时读入字符数为 0
+// 汉字数字以数字或“十”开头，可以是带单位的写法（如“二十一”“一亿二千万”），
+// 也可以是不带单位的逐位写法（如“二〇一九”）。数值不限位数
+func scanCJKNumber(token []rune) (Decimal, int) {
+	if len(token) == 0 {
+		return Decimal{}, 0
+	}
+	if _, isDigit := cjkDigitValue[token[0]]; !isDigit && cjkUnitValue[token[0]] != 10 {
+		return Decimal{}, 0
+	}
+	n := 0
+	hasUnit := false
+	for n < len(token) && IsCJKNumRune(token[n]) {
+		if cjkUnitValue[token[n]] != 0 {
+			hasUnit = true
+		}
+		n++
+	}
+	// 逐位写法
+	if !hasUnit {
+		var num Decimal
+		for _, r := range token[:n] {
+			num.integer = append(num.integer, cjkDigitValue[r])
+		}
+		for len(num.integer) > 0 && num.integer[0] == 0 {
+			num.integer = num.integer[1:]
+		}
+		return num, n
+	}
+	// 带单位的写法：section 是万以下的一节，digit 是尚未乘单位的数字
+	// 连续的“亿”可能超出 uint64 的范围，total 使用任意精度整数
+	total := new(big.Int)
+	var section, digit uint64
+	hasDigit := false
+	for _, r := range token[:n] {
+		if d, isDigit := cjkDigitValue[r]; isDigit {
+			digit = uint64(d)
+			hasDigit = true
+			continue
+		}
+		switch unit := cjkUnitValue[r]; unit {
+		case 10, 100, 1000:
+			// “十”前省略“一”，如“十二”“一百十”
+			if !hasDigit && unit == 10 {
+				digit = 1
+			}
+			section += digit * unit
+		case 10000:
+			part := new(big.Int).SetUint64(section + digit)
+			total.Add(total, part.Mul(part, big.NewInt(10000)))
+			section = 0
+		case 100000000:
+			total.Add(total, new(big.Int).SetUint64(section+digit))
+			total.Mul(total, big.NewInt(100000000))
+			section = 0
+		}
+		digit = 0
+		hasDigit = false
+	}
+	total.Add(total, new(big.Int).SetUint64(section+digit))
+	num, _ := scanDigits([]rune(total.String()))
+	return num, n
+}
+
+// 小写与大写汉字数字，下标为数值
+var (
+	cjkLowerDigits = []rune("〇一二三四五六七八九")
+	cjkUpperDigits = []rune("零壹贰叁肆伍陆柒捌玖")
+	cjkLowerUnits  = []rune("十百千")
+	cjkUpperUnits  = []rune("拾佰仟")
+)
+
+// 把非负整数写成带单位的汉字数字，如 12 写作“十二”，105 写作“一百零五”
+// upper 为真时使用大写数字，如 12 写作“拾贰”
+func FormatCJKNumber(num uint64, upper bool) string {
+	digits, units := cjkLowerDigits, cjkLowerUnits
+	if upper {
+		digits, units = cjkUpperDigits, cjkUpperUnits
+	}
+	if num == 0 {
+		return string(digits[0])
+	}
+	s := formatCJKSections(num, digits, units)
+	// “一十二”写作“十二”
+	if len(s) >= 2 && s[0] == digits[1] && s[1] == units[0] {
+		s = s[1:]
+	}
+	return string(s)
+}
+
+// 把非负整数逐位写成汉字数字，如 12 写作“一二”，2019 写作“二〇一九”
+// upper 为真时使用大写数字，如 12 写作“壹贰”
+func FormatCJKDigits(num uint64, upper bool) string {
+	digits := cjkLowerDigits
+	if upper {
+		digits = cjkUpperDigits
+	}
+	return strings.Map(func(r rune) rune {
+		return digits[r-'0']
+	}, strconv.FormatUint(num, 10))
+}
+
+// 写出正整数，万以上按“万”“亿”分节
+func formatCJKSections(num uint64, digits, units []rune) []rune {
+	if num < 10000 {
+		return formatCJKSection(num, digits, units)
+	}
+	unit, value := '万', uint64(10000)
+	if num >= 100000000 {
+		unit, value = '亿', 100000000
+	}
+	s := append(formatCJKSections(num/value, digits, units), unit)
+	low := num % value
+	if low == 0 {
+		return s
+	}
+	if low < value/10 {
+		s = append(s, '零')
+	}
+	return append(s, formatCJKSections(low, digits, units)...)
+}
+
+// 写出小于一万的正整数，中间的空位写“零”
+func formatCJKSection(num uint64, digits, units []rune) []rune {
+	var s []rune
+	zero := false
+	for i, p := 3, uint64(1000); i >= 0; i, p = i-1, p/10 {
+		d := num / p % 10
+		if d == 0 {
+			zero = len(s) > 0
+			continue
+		}
+		if zero {
+			s = append(s, '零')
+			zero = false
+		}
+		s = append(s, digits[d])
+		if i > 0 {
+			s = append(s, units[i-1])
+		}
+	}
+	return s
+}
+
+// 按格式写出标题中的数字：arabic 为阿拉伯数字，chinese 为小写汉字数字，
+// chinese_upper 为大写汉字数字，fullwidth 为全角数字
+func FormatNumber(num int, format string) string {
+	switch format {
+	case "chinese":
+		return FormatCJKNumber(uint64(num), false)
+	case "chinese_upper":
+		return FormatCJKNumber(uint64(num), true)
+	case "fullwidth":
+		// 只把数字换为全角，负号等照原样
+		return strings.Map(func(r rune) rune {
+			if '0' <= r && r <= '9' {
+				return r - '0' + '０'
+			}
+			return r
+		}, strconv.Itoa(num))
+	default:
+		return strconv.Itoa(num)
+	}
+}
+
+// 判断是否是可用的数字格式
+func isNumberFormat(format string) bool {
+	switch format {
+	case "arabic", "chinese", "chinese_upper", "fullwidth":
+		return true
+	default:
+		return false
+	}
+}
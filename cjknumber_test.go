@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanCJKNumber(t *testing.T) {
+	tests := []struct {
+		str string
+		num string
+		n   int
+	}{
+		{"二", "2", 1},
+		{"十二生肖", "12", 2},
+		{"二十一条", "21", 3},
+		{"一百零五", "105", 4},
+		{"一百十", "110", 3},
+		{"二〇一九年", "2019", 4},
+		{"一亿二千万", "120000000", 5},
+		{"十万", "100000", 2},
+		{"叁佰贰拾壹", "321", 5},
+		{"两千", "2000", 2},
+		{"一二三四五六七八九〇一二三四五六七八九〇一二", "1234567890123456789012", 22},
+		{"一亿亿亿", "1000000000000000000000000", 4},
+		{"万一", "", 0},
+		{"国", "", 0},
+	}
+	for _, test := range tests {
+		num, n := scanCJKNumber([]rune(test.str))
+		want, _ := ParseDecimal(test.num)
+		if num.Cmp(want) != 0 || n != test.n {
+			t.Errorf("scanCJKNumber(%q) = %v, %d, want %s, %d", test.str, num, n, test.num, test.n)
+		}
+	}
+}
+
+func TestStrcmp_cjkNumber(t *testing.T) {
+	s := IndexEntrySlice{colattor: ReadingIndexCollator{}, cjk_number: 1}
+	less := [][2]string{
+		{"二", "十"},
+		{"十", "二十一"},
+		{"三国", "十二生肖"},
+		{"第2条", "第二十一条"},
+		{"九九九九九九九九九九九九九九九九九九九九", "一〇〇〇〇〇〇〇〇〇〇〇〇〇〇〇〇〇〇〇〇"},
+	}
+	for _, pair := range less {
+		if cmp := s.Strcmp(pair[0], pair[1]); cmp >= 0 {
+			t.Errorf("Strcmp(%q, %q) = %d, want < 0", pair[0], pair[1], cmp)
+		}
+	}
+}
+
+func TestFormatCJKNumber(t *testing.T) {
+	tests := []struct {
+		num   uint64
+		upper bool
+		want  string
+	}{
+		{0, false, "〇"},
+		{7, false, "七"},
+		{10, false, "十"},
+		{12, false, "十二"},
+		{12, true, "拾贰"},
+		{20, false, "二十"},
+		{105, false, "一百零五"},
+		{110, false, "一百一十"},
+		{1005, false, "一千零五"},
+		{10010, false, "一万零一十"},
+		{100000, false, "十万"},
+		{120000000, false, "一亿二千万"},
+		{100000001, false, "一亿零一"},
+	}
+	for _, test := range tests {
+		if got := FormatCJKNumber(test.num, test.upper); got != test.want {
+			t.Errorf("FormatCJKNumber(%d, %v) = %q, want %q", test.num, test.upper, got, test.want)
+		}
+		if num, n := scanCJKNumber([]rune(test.want)); n != len([]rune(test.want)) {
+			t.Errorf("scanCJKNumber(%q) read %d runes", test.want, n)
+		} else if value, ok := num.Int(); !ok || uint64(value) != test.num {
+			t.Errorf("scanCJKNumber(%q) = %v", test.want, num)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		num    int
+		format string
+		want   string
+	}{
+		{12, "arabic", "12"},
+		{12, "chinese", "十二"},
+		{12, "chinese_upper", "拾贰"},
+		{12, "fullwidth", "１２"},
+		{-12, "fullwidth", "-１２"},
+	}
+	for _, test := range tests {
+		if got := FormatNumber(test.num, test.format); got != test.want {
+			t.Errorf("FormatNumber(%d, %q) = %q, want %q", test.num, test.format, got, test.want)
+		}
+	}
+}
+
+func TestOutput_cjkNumberGroup(t *testing.T) {
+	for _, method := range []string{"pinyin", "stroke", "radical"} {
+		style := newTestOutputStyle()
+		style.cjk_number_flag = 2
+		style.numhead_positive = "数字"
+		if got, want := writeTestIndex(method, style, "十二", "3", "木"), "[数字] 3 十二"; !strings.HasPrefix(got, want) {
+			t.Errorf("%s: output = %q, want prefix %q", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// 汉字按 GB2312/GBK 或 Big5 编码排序，不能编码的汉字按后备方式排在最后
+// GB2312 一级汉字按拼音排列，与英文字母一起分组；二级汉字按部首排列
+// Big5 常用字与次常用字都按笔画排列
+type CodeIndexCollator struct {
+	encoding encoding.Encoding
+	regions  []codeRegion
+	initials []codeInitial // 一级汉字按拼音排列时，各首字母的起始编码
+	fallback IndexCollator
+	codes    map[rune]uint32 // 编码缓存，0 表示不能编码
+	nested   bool            // 汉字分组放在 cjk_heading 上层分组之下
+}
+
+// 一个编码区间，如一级汉字、二级汉字
+// 编码的首字节由 first、last 限定，尾字节还须在 trail_first 至 0xfe 之间，
+// 如 GBK 中首字节在一级汉字范围内、尾字节小于 0xa1 的是 GBK/4 区的字
+type codeRegion struct {
+	first, last uint16
+	trail_first byte
+}
+
+// 汉字编码分组的次序
+const (
+	CODE_LEVEL1    = iota // 一级汉字
+	CODE_LEVEL2           // 二级汉字
+	CODE_EXTENSION        // 其他编码的汉字
+	CODE_FALLBACK         // 不能编码的汉字
+	CODE_GROUPS
+)
+
+func NewGBCodeIndexCollator(fallback IndexCollator) CodeIndexCollator {
+	return CodeIndexCollator{
+		encoding: simplifiedchinese.GBK,
+		regions: []codeRegion{
+			CODE_LEVEL1: {0xb0a1, 0xd7f9, 0xa1},
+			CODE_LEVEL2: {0xd8a1, 0xf7fe, 0xa1},
+		},
+		initials: gb2312Initials,
+		fallback: fallback,
+		codes:    make(map[rune]uint32),
+	}
+}
+
+func NewBig5CodeIndexCollator(fallback IndexCollator) CodeIndexCollator {
+	return CodeIndexCollator{
+		encoding: traditionalchinese.Big5,
+		regions: []codeRegion{
+			CODE_LEVEL1: {0xa440, 0xc67e, 0x40},
+			CODE_LEVEL2: {0xc940, 0xf9d5, 0x40},
+		},
+		fallback: fallback,
+		codes:    make(map[rune]uint32),
+	}
+}
+
+// 拼音首字母的起始编码
+type codeInitial struct {
+	code   uint16
+	letter rune
+}
+
+// GB2312 一级汉字中各拼音首字母的起始编码
+var gb2312Initials = []codeInitial{
+	{0xb0a1, 'a'}, // 啊
+	{0xb0c5, 'b'}, // 芭
+	{0xb2c1, 'c'}, // 擦
+	{0xb4ee, 'd'}, // 搭
+	{0xb6ea, 'e'}, // 蛾
+	{0xb7a2, 'f'}, // 发
+	{0xb8c1, 'g'}, // 噶
+	{0xb9fe, 'h'}, // 哈
+	{0xbbf7, 'j'}, // 击
+	{0xbfa6, 'k'}, // 喀
+	{0xc0ac, 'l'}, // 垃
+	{0xc2e8, 'm'}, // 妈
+	{0xc4c3, 'n'}, // 拿
+	{0xc5b6, 'o'}, // 哦
+	{0xc5be, 'p'}, // 啪
+	{0xc6da, 'q'}, // 期
+	{0xc8bb, 'r'}, // 然
+	{0xc8f6, 's'}, // 撒
+	{0xcbfa, 't'}, // 塌
+	{0xcdda, 'w'}, // 挖
+	{0xcef4, 'x'}, // 昔
+	{0xd1b9, 'y'}, // 压
+	{0xd4d1, 'z'}, // 匝
+}
+
+// 取得汉字的排序码：高位是编码分组，低位是编码本身；不能编码的返回 0
+func (c CodeIndexCollator) code(r rune) uint32 {
+	if key, ok := c.codes[r]; ok {
+		return key
+	}
+	var key uint32
+	if unicode.Is(unicode.Han, r) {
+		if bytes, err := c.encoding.NewEncoder().String(string(r)); err == nil && len(bytes) == 2 {
+			code := uint16(bytes[0])<<8 | uint16(bytes[1])
+			region := CODE_EXTENSION
+			for i, rg := range c.regions {
+				if rg.first <= code && code <= rg.last && rg.trail_first <= bytes[1] && bytes[1] <= 0xfe {
+					region = i
+					break
+				}
+			}
+			key = uint32(region+1)<<16 | uint32(code)
+		}
+	}
+	c.codes[r] = key
+	return key
+}
+
+// 取得已编码汉字的编码分组
+func codeRegionOf(key uint32) int {
+	return int(key>>16) - 1
+}
+
+func (c CodeIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
+	// 分组：符号、数字、字母 A..Z、一级汉字、二级汉字、其他编码汉字、不能编码的汉字
+	groups := make([]IndexGroup, 2+26+CODE_GROUPS)
+	if style.headings_flag > 0 {
+		groups[0].name = style.symhead_positive
+		groups[1].name = style.numhead_positive
+		for alph, i := 'A', 2; alph <= 'Z'; alph++ {
+			groups[i].name = string(alph)
+			i++
+		}
+	} else if style.headings_flag < 0 {
+		groups[0].name = style.symhead_negative
+		groups[1].name = style.numhead_negative
+		for alph, i := 'a', 2; alph <= 'z'; alph++ {
+			groups[i].name = string(alph)
+			i++
+		}
+	}
+	groups[2+26+CODE_LEVEL1].name = style.code_level1_heading
+	groups[2+26+CODE_LEVEL2].name = style.code_level2_heading
+	groups[2+26+CODE_EXTENSION].name = style.code_extension_heading
+	groups[2+26+CODE_FALLBACK].name = style.code_fallback_heading
+	return nestCJKGroups(groups, c.nested, style)
+}
+
+// 取得分组
+func (c CodeIndexCollator) Group(entry *IndexEntry) []int {
+	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
+	first = unicode.ToLower(first)
+	key := c.code(first)
+	switch {
+	case IsNumString(entry.level[0].key):
+		return c.NumberGroup()
+	case 'a' <= first && first <= 'z':
+		return []int{2 + int(first) - 'a'}
+	case key != 0:
+		region := codeRegionOf(key)
+		if region == CODE_LEVEL1 && c.initials != nil {
+			// 一级汉字按拼音首字母分组
+			letter := 'a'
+			for _, initial := range c.initials {
+				if uint32(initial.code) <= key&0xffff {
+					letter = initial.letter
+				}
+			}
+			return []int{2 + int(letter) - 'a'}
+		}
+		return cjkGroupPath(region, c.nested)
+	case c.fallback.IsLetter(first):
+		return cjkGroupPath(CODE_FALLBACK, c.nested)
+	default:
+		// 符号组
+		return []int{0}
+	}
+}
+
+// 数字分组的路径
+func (_ CodeIndexCollator) NumberGroup() []int {
+	return []int{1}
+}
+
+// 按汉字编码比较两个字符大小
+// 非汉字在前，然后是按编码分组和编码排序的汉字，不能编码的汉字按后备方式排在最后
+func (c CodeIndexCollator) RuneCmp(a, b rune) int {
+	a_key, b_key := c.code(a), c.code(b)
+	switch {
+	case a_key == 0 && b_key == 0:
+		return c.fallback.RuneCmp(a, b)
+	case a_key == 0 && b_key != 0:
+		if c.isFallbackHan(a) {
+			return 1
+		}
+		return -1
+	case a_key != 0 && b_key == 0:
+		if c.isFallbackHan(b) {
+			return -1
+		}
+		return 1
+	case a_key < b_key:
+		return -1
+	case a_key > b_key:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// 判断是否是不能编码、由后备方式排序的汉字
+func (c CodeIndexCollator) isFallbackHan(r rune) bool {
+	lower := unicode.ToLower(r)
+	return c.fallback.IsLetter(r) && !('a' <= lower && lower <= 'z')
+}
+
+// 判断是否字母或汉字
+func (c CodeIndexCollator) IsLetter(r rune) bool {
+	r = unicode.ToLower(r)
+	switch {
+	case 'a' <= r && r <= 'z':
+		return true
+	case c.code(r) != 0:
+		return true
+	default:
+		return c.fallback.IsLetter(r)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"errors"
 	"io"
 	"log"
@@ -41,9 +42,66 @@ func NewInputIndex(option *InputOptions, style *InputStyle) *InputIndex {
 		pentry := iter.Item().(*IndexEntry)
 		in = append(in, *pentry)
 	}
+	in.unifyLevels(style)
 	return &in
 }
 
+// 同一索引项在不同条目中的各级共享人名标记和注音，使其排序、输出一致
+// 同一项有不同的注音时，使用先出现的。人名只要有一处标记，各处都按人名排序，
+// 其中西文人名的排序项改为“姓, 名”的形式，不影响输出的文字
+func (in InputIndex) unifyLevels(style *InputStyle) {
+	names := make(map[string]bool)
+	readings := make(map[string]string)
+	for _, entry := range in {
+		for i := range entry.level {
+			path := levelPath(entry.level[:i+1])
+			if entry.level[i].name {
+				names[path] = true
+			}
+			if reading := entry.level[i].reading; reading != "" && readings[path] == "" {
+				readings[path] = reading
+			}
+		}
+	}
+	if len(names) == 0 && len(readings) == 0 {
+		return
+	}
+	// 父项与子项的各级可能共用同一存储，先找出要改的各级，再逐个改写一次
+	var levels []*IndexEntryLevel
+	seen := make(map[*IndexEntryLevel]bool)
+	for _, entry := range in {
+		for i := range entry.level {
+			path := levelPath(entry.level[:i+1])
+			level := &entry.level[i]
+			if seen[level] {
+				continue
+			}
+			seen[level] = true
+			if names[path] {
+				level.name = true
+			}
+			if level.reading == "" {
+				level.reading = readings[path]
+			}
+			levels = append(levels, level)
+		}
+	}
+	for _, level := range levels {
+		if level.name {
+			level.key = LatinNameKey(level.key, style)
+		}
+	}
+}
+
+// 索引项各级的路径，用作 map 的键
+func levelPath(levels []IndexEntryLevel) string {
+	var path []string
+	for _, level := range levels {
+		path = append(path, level.key, level.text)
+	}
+	return strings.Join(path, "\x00")
+}
+
 func readIdxFile(inset *rbtree.Tree, idxfile *os.File, option *InputOptions, style *InputStyle) {
 	log.Printf("读取输入文件 %s ……\n", idxfile.Name())
 	accepted, rejected := 0, 0
@@ -69,6 +127,14 @@ func readIdxFile(inset *rbtree.Tree, idxfile *os.File, option *InputOptions, sty
 			if old := inset.Get(entry); old != nil {
 				oldentry := old.(*IndexEntry)
 				oldentry.pagelist = append(oldentry.pagelist, entry.pagelist...)
+				if last := len(entry.level) - 1; entry.level[last].name {
+					oldentry.level[last].name = true
+				}
+				for i := range entry.level {
+					if oldentry.level[i].reading == "" {
+						oldentry.level[i].reading = entry.level[i].reading
+					}
+				}
 			} else {
 				// entry 不在集合 inset 中时，插入 entry 本身和所有祖先节点，祖先不含页码
 				for len(entry.level) > 0 {
@@ -89,6 +155,35 @@ func readIdxFile(inset *rbtree.Tree, idxfile *os.File, option *InputOptions, sty
 	log.Printf("接受 %d 项，拒绝 %d 项。\n", accepted, rejected)
 }
 
+// 读入页码列表文件，每行一个页码，按页码在文档中的次序排列
+// 文件可以由文档在每页输出时写出 \thepage 得到；不能识别的行忽略
+func ReadPageList(name string, option *InputOptions, style *InputStyle) []*Page {
+	log.Printf("读取页码列表 %s ……\n", name)
+	file, err := os.Open(name)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	defer file.Close()
+	var pages []*Page
+	scanner := bufio.NewScanner(transform.NewReader(file, option.decoder))
+	for line := 1; scanner.Scan(); line++ {
+		token := strings.TrimSpace(scanner.Text())
+		if token == "" {
+			continue
+		}
+		page := &Page{encap: "", rangetype: PAGE_NORMAL}
+		if err := page.scanLocator([]rune(token), style); err != nil {
+			log.Printf("%s:%d: 不能识别的页码 %s\n", name, line, token)
+			continue
+		}
+		pages = append(pages, page)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalln(err.Error())
+	}
+	return pages
+}
+
 // 跳过空白符和行注释
 func skipspaces(reader *NumberdReader, style *InputStyle) error {
 	for {
@@ -131,6 +226,7 @@ func ScanIndexEntry(reader *NumberdReader, option *InputOptions, style *InputSty
 		SCAN_OPEN = iota
 		SCAN_KEY
 		SCAN_VALUE
+		SCAN_READING
 		SCAN_COMMAND
 		SCAN_PAGE
 		SCAN_PAGERANGE
@@ -185,6 +281,8 @@ L_scan_kv:
 				}
 			} else if r == style.actual {
 				push_keyval(SCAN_VALUE)
+			} else if r == style.reading_actual && style.reading_actual != 0 {
+				push_keyval(SCAN_READING)
 			} else if r == style.encap {
 				push_keyval(SCAN_PAGERANGE)
 			} else if r == style.level {
@@ -222,6 +320,8 @@ L_scan_kv:
 					token = append(token, r)
 					arg_depth--
 				}
+			} else if r == style.reading_actual && style.reading_actual != 0 {
+				set_value(SCAN_READING)
 			} else if r == style.encap {
 				set_value(SCAN_PAGERANGE)
 			} else if r == style.level {
@@ -236,12 +336,50 @@ L_scan_kv:
 			} else {
 				escaped = false
 			}
+		case SCAN_READING:
+			// 用户指定的注音，原样输出
+			set_reading := func(next int) {
+				str := string(token)
+				entry.level[len(entry.level)-1].reading = strings.TrimSpace(str)
+				token = nil
+				state = next
+			}
+			if quoted {
+				token = append(token, r)
+				quoted = false
+				break
+			} else if r == style.arg_open && !escaped {
+				token = append(token, r)
+				arg_depth++
+			} else if r == style.arg_close && !escaped {
+				if arg_depth == 0 {
+					set_reading(0)
+					break L_scan_kv
+				} else {
+					token = append(token, r)
+					arg_depth--
+				}
+			} else if r == style.encap {
+				set_reading(SCAN_PAGERANGE)
+			} else if r == style.level {
+				set_reading(SCAN_KEY)
+			} else if r == style.quote && !escaped {
+				quoted = true
+			} else {
+				token = append(token, r)
+			}
+			if r == style.escape {
+				escaped = true
+			} else {
+				escaped = false
+			}
 		case SCAN_PAGERANGE:
 			if quoted {
 				token = append(token, r)
 				quoted = false
 				break
-			} else if r == style.arg_open || r == style.arg_close || r == style.actual || r == style.encap || r == style.level {
+			} else if r == style.arg_open || r == style.arg_close || r == style.actual || r == style.encap || r == style.level ||
+				(r == style.reading_actual && style.reading_actual != 0) {
 				// 注意 encap 符号后不能直接加 arg_open、arg_close 等符号
 				return nil, ScanSyntaxError
 			} else if r == style.range_open {
@@ -314,8 +452,7 @@ L_scan_page:
 			}
 		case SCAN_PAGE:
 			if r == style.arg_close {
-				page.numbers, err = scanPage(token, style.page_compositor)
-				if err != nil {
+				if err := page.scanLocator(token, style); err != nil {
 					return nil, err
 				}
 				break L_scan_page
@@ -327,9 +464,13 @@ L_scan_page:
 		default:
 			panic("扫描状态错误")
 		}
-		// 未实现对 style.page_compositor 的处理
 	}
-	page.compositor = style.page_compositor
+	// 用 surname_encap 标记的索引项按人名排序，并删去此 encap
+	// 西文人名的排序项在读入全部索引项后由 unifyLevels 改写
+	if style.surname_encap != "" && page.encap == style.surname_encap {
+		page.encap = ""
+		entry.level[len(entry.level)-1].name = true
+	}
 	entry.pagelist = append(entry.pagelist, page)
 	// debug.Println(entry) //// DEBUG only
 	return &entry, nil
@@ -370,8 +511,10 @@ func CompareIndexEntry(a, b rbtree.Item) int {
 
 // 一条索引条目中的一级
 type IndexEntryLevel struct {
-	key  string
-	text string
+	key     string
+	text    string
+	name    bool   // 是否按人名排序
+	reading string // 用户指定的注音，为空时由程序计算
 }
 
 type RangeType int
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 西文人名姓氏前缀（如 von, van der）的处理方式
+const (
+	PARTICLE_IGNORE      = iota // 前缀不参与姓氏排序，移到名字之后
+	PARTICLE_KEEP               // 前缀是姓氏的一部分
+	PARTICLE_CAPITALIZED        // 大写开头的前缀是姓氏的一部分，小写的不参与排序
+)
+
+// 生成西文人名的排序项，形如“姓, 名 前缀”
+// 输入可以是“名 姓”（如 Karl Marx）或“姓, 名”（如 von Neumann, John）的形式，
+// 姓在前的东亚人名（如 Sun Yat-sen）保持原来的次序。不以拉丁字母开头的串不变
+func LatinNameKey(name string, style *InputStyle) string {
+	first, _ := utf8.DecodeRuneInString(name)
+	if !unicode.Is(unicode.Latin, first) {
+		return name
+	}
+	var surname, given []string
+	if comma := strings.Index(name, ","); comma >= 0 {
+		surname = strings.Fields(name[:comma])
+		given = strings.Fields(name[comma+1:])
+	} else if words := strings.Fields(name); style.isSurnameFirst(words) {
+		surname, given = words[:1], words[1:]
+	} else {
+		// 最后一个词是姓氏，其前的前缀也属于姓氏
+		i := len(words) - 1
+		for i > 0 && style.isNameParticle(words[i-1]) {
+			i--
+		}
+		surname, given = words[i:], words[:i]
+	}
+	// 分离姓氏的前缀
+	var particles []string
+	for len(surname) > 1 && style.isNameParticle(surname[0]) {
+		keep := false
+		switch style.name_particle_flag {
+		case PARTICLE_KEEP:
+			keep = true
+		case PARTICLE_CAPITALIZED:
+			r, _ := utf8.DecodeRuneInString(surname[0])
+			keep = unicode.IsUpper(r)
+		}
+		if keep {
+			break
+		}
+		particles = append(particles, surname[0])
+		surname = surname[1:]
+	}
+	if style.name_mc_flag != 0 && len(surname) > 0 {
+		surname[0] = mcToMac(surname[0])
+	}
+	key := strings.Join(surname, " ")
+	rest := append(append([]string{}, given...), particles...)
+	if len(rest) > 0 {
+		key += ", " + strings.Join(rest, " ")
+	}
+	return key
+}
+
+// 判断没有逗号的人名是否姓在前：首词是 name_surname_first 中的姓氏，
+// 或者只有两个词且名字是小写音节以连字符相连的形式（如 Yat-sen、Kai-shek）
+// name_surname_first 为空（默认）时不识别姓在前的人名
+func (style *InputStyle) isSurnameFirst(words []string) bool {
+	if len(words) < 2 || strings.TrimSpace(style.name_surname_first) == "" {
+		return false
+	}
+	for _, s := range strings.Fields(style.name_surname_first) {
+		if strings.EqualFold(s, words[0]) {
+			return true
+		}
+	}
+	if len(words) == 2 {
+		if hyphen := strings.Index(words[1], "-"); hyphen > 0 {
+			r, _ := utf8.DecodeRuneInString(words[1][hyphen+1:])
+			return unicode.IsLower(r)
+		}
+	}
+	return false
+}
+
+// 判断是否是姓氏前缀，不区分大小写
+func (style *InputStyle) isNameParticle(word string) bool {
+	for _, p := range strings.Fields(style.name_particles) {
+		if strings.EqualFold(p, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// 将 McDonald 形式的姓氏按 MacDonald 排序
+func mcToMac(surname string) string {
+	if strings.HasPrefix(surname, "Mc") {
+		if r, _ := utf8.DecodeRuneInString(surname[2:]); unicode.IsUpper(r) {
+			return "Mac" + surname[2:]
+		}
+	}
+	return surname
+}
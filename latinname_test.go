@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLatinNameKey(t *testing.T) {
+	style := NewInputStyle()
+	tests := []struct {
+		name, key string
+	}{
+		{"Karl Marx", "Marx, Karl"},
+		{"Marx, Karl", "Marx, Karl"},
+		{"John von Neumann", "Neumann, John von"},
+		{"von Neumann, John", "Neumann, John von"},
+		{"Ludwig van der Rohe", "Rohe, Ludwig van der"},
+		{"Plato", "Plato"},
+		{"Sun Yat-sen", "Yat-sen, Sun"},
+		{"Mao Zedong", "Zedong, Mao"},
+		{"Robert Lee", "Lee, Robert"},
+		{"Lee, Robert", "Lee, Robert"},
+		{"Anna Wong-Smith", "Wong-Smith, Anna"},
+		{"Jean-Paul Sartre", "Sartre, Jean-Paul"},
+		{"孙中山", "孙中山"},
+	}
+	for _, test := range tests {
+		if key := LatinNameKey(test.name, style); key != test.key {
+			t.Errorf("LatinNameKey(%q) = %q, want %q", test.name, key, test.key)
+		}
+	}
+	// 设置 name_surname_first 后识别姓在前的人名
+	style.name_surname_first = "Mao Kim"
+	for name, want := range map[string]string{
+		"Sun Yat-sen":      "Sun, Yat-sen",
+		"Mao Zedong":       "Mao, Zedong",
+		"Kim Il-sung":      "Kim, Il-sung",
+		"Robert Lee":       "Lee, Robert",
+		"Anna Wong-Smith":  "Wong-Smith, Anna",
+		"Jean-Paul Sartre": "Sartre, Jean-Paul",
+	} {
+		if key := LatinNameKey(name, style); key != want {
+			t.Errorf("LatinNameKey(%q) with name_surname_first = %q, want %q", name, key, want)
+		}
+	}
+	style.name_surname_first = ""
+	style.name_particle_flag = PARTICLE_CAPITALIZED
+	style.name_mc_flag = 1
+	for name, want := range map[string]string{
+		"Charles De Gaulle": "De Gaulle, Charles",
+		"John von Neumann":  "Neumann, John von",
+		"Ronald McDonald":   "MacDonald, Ronald",
+	} {
+		if key := LatinNameKey(name, style); key != want {
+			t.Errorf("LatinNameKey(%q) = %q, want %q", name, key, want)
+		}
+	}
+}
+
+func TestUnifyLevels_name(t *testing.T) {
+	style := NewInputStyle()
+	marx := IndexEntryLevel{key: "Karl Marx", text: "Karl Marx"}
+	marked := marx
+	marked.name = true
+	in := InputIndex{
+		{level: []IndexEntryLevel{marx}},
+		{level: []IndexEntryLevel{marked}},
+		{level: []IndexEntryLevel{marx, {key: "works", text: "works"}}},
+	}
+	in.unifyLevels(style)
+	for _, entry := range in {
+		if level := entry.level[0]; !level.name || level.key != "Marx, Karl" || level.text != "Karl Marx" {
+			t.Errorf("level = %+v, want name key \"Marx, Karl\"", level)
+		}
+	}
+}
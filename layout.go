@@ -0,0 +1,232 @@
+package main
+
+import (
+	"log"
+	"strings"
+)
+
+// 分组的种类，各 collator 的分组都依次是符号、数字、26 个字母和汉字分组
+const (
+	GROUP_SYMBOL = iota // 符号
+	GROUP_NUMBER        // 数字
+	GROUP_LETTER        // 字母
+	GROUP_CJK           // 汉字（笔画、部首等）
+	GROUP_KINDS
+)
+
+var groupKindNames = map[string]int{
+	"symbols": GROUP_SYMBOL,
+	"numbers": GROUP_NUMBER,
+	"letters": GROUP_LETTER,
+	"cjk":     GROUP_CJK,
+}
+
+// 取得分组种类的名字
+func groupKindName(kind int) string {
+	for name, k := range groupKindNames {
+		if k == kind {
+			return name
+		}
+	}
+	return ""
+}
+
+// cjk_heading 非空时，各 collator 把符号、数字与字母分组之后的汉字分组放到以此为名的
+// 上层分组之下，汉字分组的路径因此多一层
+func nestCJKGroups(groups []IndexGroup, nested bool, style *OutputStyle) []IndexGroup {
+	if !nested {
+		return groups
+	}
+	cjk := IndexGroup{name: style.cjk_heading, subgroups: groups[2+26:]}
+	return append(groups[:2+26:2+26], cjk)
+}
+
+// 第 i 个汉字分组在 InitGroups 所得的树中的路径
+func cjkGroupPath(i int, nested bool) []int {
+	if nested {
+		return []int{2 + 26, i}
+	}
+	return []int{2 + 26 + i}
+}
+
+// 取得 InitGroups 所得第 i 个分组的种类
+func groupKind(i int) int {
+	switch {
+	case i == 0:
+		return GROUP_SYMBOL
+	case i == 1:
+		return GROUP_NUMBER
+	case i < 2+26:
+		return GROUP_LETTER
+	default:
+		return GROUP_CJK
+	}
+}
+
+// group_order 中的一项：若干种分组，以及可选的分组名
+type groupLayoutItem struct {
+	kinds []int
+	name  string
+	named bool
+}
+
+// 解析 group_order，如 "cjk letters symbols+numbers=符号与数字"
+// 各项以空白分隔，一项中的多种分组以 + 连接，= 后是分组名；未列出的分组种类按默认次序排在最后
+func parseGroupOrder(order string) []groupLayoutItem {
+	var items []groupLayoutItem
+	listed := make([]bool, GROUP_KINDS)
+	for _, field := range strings.Fields(order) {
+		var item groupLayoutItem
+		if eq := strings.Index(field, "="); eq >= 0 {
+			item.name, item.named = field[eq+1:], true
+			field = field[:eq]
+		}
+		for _, name := range strings.Split(field, "+") {
+			kind, ok := groupKindNames[name]
+			if !ok {
+				log.Fatalln("未知分组种类", name)
+			}
+			if listed[kind] {
+				log.Fatalln("分组种类重复", name)
+			}
+			listed[kind] = true
+			item.kinds = append(item.kinds, kind)
+		}
+		items = append(items, item)
+	}
+	for kind := 0; kind < GROUP_KINDS; kind++ {
+		if !listed[kind] {
+			items = append(items, groupLayoutItem{kinds: []int{kind}})
+		}
+	}
+	return items
+}
+
+// 原分组在重排后的位置
+type groupRef struct {
+	path   []int // 新分组的路径
+	merged bool  // 是否与其他分组合并，合并后原分组的子分组不再保留
+}
+
+// 按格式文件中的 group_order 重排、合并 InitGroups 所得的分组
+// 返回新的分组，以及各原分组在新分组中的位置
+func layoutGroups(groups []IndexGroup, style *OutputStyle) ([]IndexGroup, []groupRef) {
+	var layout []IndexGroup
+	refs := make([]groupRef, len(groups))
+	for i := range groups {
+		setGroupKind(&groups[i], groupKind(i))
+	}
+	for _, item := range parseGroupOrder(style.group_order) {
+		var members []int
+		for _, kind := range item.kinds {
+			for i := range groups {
+				if groupKind(i) == kind {
+					members = append(members, i)
+				}
+			}
+		}
+		switch {
+		case len(item.kinds) > 1:
+			// 合并为一个分组，默认使用第一个分组的名字
+			merged := IndexGroup{name: item.name, kind: item.kinds[0]}
+			if !item.named && len(members) > 0 {
+				merged.name = groups[members[0]].name
+			}
+			for _, m := range members {
+				refs[m] = groupRef{path: []int{len(layout)}, merged: true}
+			}
+			layout = append(layout, merged)
+		case item.named && len(members) == 1:
+			// 单个分组改名
+			group := groups[members[0]]
+			group.name = item.name
+			group.name_prefix, group.name_suffix = "", ""
+			refs[members[0]] = groupRef{path: []int{len(layout)}}
+			layout = append(layout, group)
+		case item.named:
+			// 多个分组放在一个上层分组之下
+			super := IndexGroup{name: item.name, kind: item.kinds[0]}
+			for j, m := range members {
+				refs[m] = groupRef{path: []int{len(layout), j}}
+				super.subgroups = append(super.subgroups, groups[m])
+			}
+			layout = append(layout, super)
+		default:
+			for _, m := range members {
+				refs[m] = groupRef{path: []int{len(layout)}}
+				layout = append(layout, groups[m])
+			}
+		}
+	}
+	return layout, refs
+}
+
+// 设置分组及其各层子分组的种类
+func setGroupKind(group *IndexGroup, kind int) {
+	group.kind = kind
+	for i := range group.subgroups {
+		setGroupKind(&group.subgroups[i], kind)
+	}
+}
+
+// 合并相邻的稀疏分组，即项数少于 group_min_size 的非空分组
+// 只有紧挨着的 mergeable 同种分组才合并，合并后的项数达到 group_min_size 即不再并入后面的
+// 分组；合并后的分组名形如“X–Z”“1–3 画”或“X, Y, Z”
+// 各分组的索引项按原来的次序接在一起，相邻的同名子分组（如笔画分组中的“横”）合为一个
+func mergeSparseGroups(groups []IndexGroup, style *OutputStyle, mergeable func(kind int) bool) []IndexGroup {
+	if style.group_min_size <= 0 {
+		return groups
+	}
+	sparse := func(group *IndexGroup) bool {
+		return !group.empty() && group.count() < style.group_min_size
+	}
+	var result []IndexGroup
+	for i := 0; i < len(groups); i++ {
+		group := groups[i]
+		if group.dynamic || !mergeable(group.kind) || !sparse(&group) {
+			result = append(result, group)
+			continue
+		}
+		// 找出紧接着的稀疏分组，空分组将其隔开
+		run := []int{i}
+		size := group.count()
+		for j := i + 1; j < len(groups) && size < style.group_min_size; j++ {
+			if groups[j].kind != group.kind || !sparse(&groups[j]) {
+				break
+			}
+			run = append(run, j)
+			size += groups[j].count()
+		}
+		if len(run) == 1 {
+			result = append(result, group)
+			continue
+		}
+		// 各分组名有相同的前后缀时，分组名中只写一次，如“1–3 画”
+		prefix, suffix := group.name_prefix, group.name_suffix
+		for _, j := range run {
+			if groups[j].name_prefix != prefix || groups[j].name_suffix != suffix {
+				prefix, suffix = "", ""
+				break
+			}
+		}
+		var names []string
+		for _, j := range run {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(groups[j].name, prefix), suffix))
+			if j != i {
+				group.merge(&groups[j])
+			}
+		}
+		if style.group_merge_list_flag != 0 {
+			group.name = prefix + strings.Join(names, style.group_merge_joiner) + suffix
+		} else {
+			group.name = prefix + names[0] + style.group_merge_joiner + names[len(names)-1] + suffix
+		}
+		result = append(result, group)
+		i = run[len(run)-1]
+	}
+	// 上层分组之下的分组
+	for i := range result {
+		result[i].subgroups = mergeSparseGroups(result[i].subgroups, style, mergeable)
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"strings"
+)
+
+// 格式文件中 locator_class 定义的页码类，如“表 3.2”“式(4-7)”“§12”
+// 页码类由若干数字分量和分量前后的文字组成，文字起到前缀、分隔符的作用
+type LocatorClass struct {
+	spec       string      // 原始定义
+	literals   []string    // 各数字分量前后的文字，比 formats 多一项
+	formats    []NumFormat // 各数字分量的格式，NUM_UNKNOWN 表示自动判断
+	precedence int         // 与普通页码及其他页码类的次序，普通页码为 0
+	index      int         // 定义的次序，从 1 开始，precedence 相同时按此排序
+	merge      bool        // 是否把连续的页码合并为区间
+}
+
+// 页码类定义中表示数字分量的占位符
+var locatorComponents = map[rune]NumFormat{
+	'n': NUM_ARABIC,
+	'r': NUM_ROMAN_LOWER,
+	'R': NUM_ROMAN_UPPER,
+	'a': NUM_ALPH_LOWER,
+	'A': NUM_ALPH_UPPER,
+	'c': NUM_CJK_LOWER,
+	'C': NUM_CJK_UPPER,
+	'u': NUM_UROMAN_LOWER,
+	'U': NUM_UROMAN_UPPER,
+	'f': NUM_FULLWIDTH,
+	'p': NUM_UNKNOWN,
+}
+
+// 解析页码类定义，如 "式(%n-%n)"、"Table %n.%n; precedence=-1; range=0"
+// 模式中 %n、%r 等表示数字分量，%% 与 %; 表示 % 与 ; 本身；分号后是选项：
+// precedence 为与普通页码（0）及其他页码类的次序，默认依定义次序排在普通页码之后；
+// range 为 0 时不把连续的页码合并为区间
+func parseLocatorClass(spec string, index int) *LocatorClass {
+	class := &LocatorClass{spec: spec, precedence: 1, index: index, merge: true}
+	var literal []rune
+	var options []string
+	pattern := []rune(spec)
+L_pattern:
+	for i := 0; i < len(pattern); i++ {
+		switch r := pattern[i]; {
+		case r == ';':
+			options = strings.Split(string(pattern[i+1:]), ";")
+			break L_pattern
+		case r != '%':
+			literal = append(literal, r)
+		case i+1 == len(pattern):
+			log.Fatalln("页码类定义错误", spec)
+		default:
+			i++
+			if pattern[i] == '%' || pattern[i] == ';' {
+				literal = append(literal, pattern[i])
+				continue
+			}
+			format, ok := locatorComponents[pattern[i]]
+			if !ok {
+				log.Fatalln("页码类定义错误", spec)
+			}
+			class.literals = append(class.literals, string(literal))
+			class.formats = append(class.formats, format)
+			literal = nil
+		}
+	}
+	class.literals = append(class.literals, string(literal))
+	if len(class.formats) == 0 {
+		log.Fatalln("页码类中没有数字", spec)
+	}
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		eq := strings.Index(option, "=")
+		if eq < 0 {
+			log.Fatalln("页码类选项错误", option)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(option[eq+1:]))
+		if err != nil {
+			log.Fatalln("页码类选项错误", option)
+		}
+		switch strings.TrimSpace(option[:eq]) {
+		case "precedence":
+			class.precedence = value
+		case "range":
+			class.merge = value != 0
+		default:
+			log.Fatalln("未知页码类选项", option)
+		}
+	}
+	return class
+}
+
+// 按页码类读入页码，不符合模式时返回 false
+// 数字分量读入尽可能多的该格式字符，自动判断格式的分量读到下一段文字为止
+func (class *LocatorClass) scan(token []rune, alph bool) ([]PageNumber, bool) {
+	s := string(token)
+	if !strings.HasPrefix(s, class.literals[0]) {
+		return nil, false
+	}
+	s = s[len(class.literals[0]):]
+	var nums []PageNumber
+	for i, format := range class.formats {
+		next := class.literals[i+1]
+		end := 0
+		if format == NUM_UNKNOWN {
+			switch {
+			case i+1 == len(class.formats) && next == "":
+				end = len(s)
+			case next == "":
+				log.Fatalln("页码类中自动判断格式的数字后必须有文字", class.spec)
+			default:
+				end = strings.Index(s, next)
+			}
+		} else {
+			end = strings.IndexFunc(s, func(r rune) bool {
+				return !isNumFormatRune(format, r)
+			})
+			if end < 0 {
+				end = len(s)
+			}
+		}
+		if end <= 0 || !strings.HasPrefix(s[end:], next) {
+			return nil, false
+		}
+		var pn PageNumber
+		var err error
+		if format == NUM_UNKNOWN {
+			pn, err = scanNumber([]rune(s[:end]), alph)
+		} else {
+			pn.format = format
+			pn.num, err = scanNumberAs([]rune(s[:end]), format)
+			if format == NUM_CJK_LOWER || format == NUM_CJK_UPPER {
+				pn.cjk = cjkNumStyleOf([]rune(s[:end]))
+			}
+		}
+		if err != nil {
+			return nil, false
+		}
+		nums = append(nums, pn)
+		s = s[end+len(next):]
+	}
+	if s != "" {
+		return nil, false
+	}
+	return nums, true
+}
+
+// 按页码类写出页码
+func (class *LocatorClass) format(numbers []PageNumber) string {
+	if len(numbers) == 0 {
+		return ""
+	}
+	var s []string
+	for i, pn := range numbers {
+		s = append(s, class.literals[i], pn.String())
+	}
+	s = append(s, class.literals[len(numbers)])
+	return strings.Join(s, "")
+}
+
+// 比较两个页码类的次序，nil 表示普通页码
+func compareLocatorClass(a, b *LocatorClass) int {
+	a_prec, a_index, b_prec, b_index := 0, 0, 0, 0
+	if a != nil {
+		a_prec, a_index = a.precedence, a.index
+	}
+	if b != nil {
+		b_prec, b_index = b.precedence, b.index
+	}
+	if a_prec != b_prec {
+		return a_prec - b_prec
+	}
+	return a_index - b_index
+}
+
+// 按格式文件中的页码类读入页码，都不符合时按普通页码读入
+func (page *Page) scanLocator(token []rune, style *InputStyle) error {
+	for _, class := range style.locator_classes {
+		if nums, ok := class.scan(token, style.page_alph_flag != 0); ok {
+			page.numbers, page.class = nums, class
+			return nil
+		}
+	}
+	var err error
+	page.numbers, page.compositor, err = scanPage(token, style.pageCompositors(), style.page_alph_flag != 0)
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLocatorClass(t *testing.T) {
+	tests := []struct {
+		spec  string
+		input string
+		ok    bool
+	}{
+		{"Table %n.%n", "Table 3.2", true},
+		{"Table %n.%n", "Table 3", false},
+		{"式(%n-%n)", "式(4-7)", true},
+		{"§%n", "§12", true},
+		{"%A-%n", "A-3", true},
+		{"%A-%n", "a-3", false},
+		{"附录%p", "附录iv", true},
+		{"第%c章%%%;; range=0", "第三章%;", true},
+	}
+	for i, test := range tests {
+		class := parseLocatorClass(test.spec, i+1)
+		nums, ok := class.scan([]rune(test.input), false)
+		if ok != test.ok {
+			t.Errorf("%q.scan(%q) = %v, want %v", test.spec, test.input, ok, test.ok)
+			continue
+		}
+		if ok {
+			if s := class.format(nums); s != test.input {
+				t.Errorf("%q.format(%v) = %q, want %q", test.spec, nums, s, test.input)
+			}
+		}
+	}
+}
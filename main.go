@@ -58,6 +58,9 @@ func main() {
 	instyle, outstyle := NewStyles(&option.StyleOptions)
 
 	in := NewInputIndex(&option.InputOptions, instyle)
+	if option.pagelist_file != "" {
+		option.pagelist = ReadPageList(option.pagelist_file, &option.InputOptions, instyle)
+	}
 	log.Printf("合并后共 %d 项。\n", len(*in))
 
 	log.Println("正在排序……")
@@ -96,9 +99,12 @@ type OutputOptions struct {
 	encoder       transform.Transformer // 由 encoding 生成
 	output        string
 	sort          string
+	word          bool
 	page          string
 	strict        bool
 	disable_range bool
+	pagelist_file string
+	pagelist      []*Page // 由 pagelist_file 读入
 }
 
 type StyleOptions struct {
@@ -112,11 +118,13 @@ func NewOptions() *Options {
 	flag.BoolVar(&o.stdin, "i", false, "从标准输入读取")
 	flag.StringVar(&o.output, "o", "", "输出文件")
 	flag.StringVar(&o.sort, "z", "pinyin",
-		"中文分组排序方式，可以使用 pinyin (reading)、bihua (stroke) 或 bushou (radical)")
+		"中文分组排序方式，可以使用 pinyin (reading)、bihua (stroke)、bushou (radical)、gbcode 或 big5code")
+	flag.BoolVar(&o.word, "word", false, "按拼音排序时整词比较，先比较无声调拼音，再比较声调和汉字")
 	// flag.StringVar(&o.page, "p", "", "设置起始页码") // 未实现
 	flag.BoolVar(&o.quiet, "q", false, "静默模式，不输出错误信息")
 	flag.BoolVar(&o.disable_range, "r", false, "禁用自动生成页码区间")
 	flag.BoolVar(&o.strict, "strict", false, "严格区分不同 encapsulated 命令的页码")
+	flag.StringVar(&o.pagelist_file, "pagelist", "", "按文档次序列出各页页码的文件，用于在不同数字格式处断开页码区间")
 	flag.StringVar(&o.style, "s", "", "格式文件名")
 	flag.StringVar(&o.log, "t", "", "日志文件名")
 	flag.StringVar(&o.encoding, "enc", "utf-8", "读写索引文件的编码")
@@ -219,7 +227,8 @@ func stripExt(fpath string) string {
 func Usage() {
 	fmt.Fprintln(os.Stderr, `用法：
 zhmakeindex [-c] [-i] [-o <ind>] [-q] [-r] [-s <sty>] [-t <log>]
-            [-enc <enc>] [-senc <senc>] [-strict] [-z <sort>]
+            [-enc <enc>] [-senc <senc>] [-pagelist <file>] [-strict] [-word]
+            [-z <sort>]
             [<输入文件1> <输入文件2> ...]`)
 	fmt.Fprintln(os.Stderr, "\n中文索引处理程序")
 	fmt.Fprintf(os.Stderr, "\n  %-10s %-5s %s\n", "选项", "默认值", "说明")
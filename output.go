@@ -5,6 +5,8 @@ import (
 	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"golang.org/x/text/transform"
 )
@@ -17,7 +19,7 @@ type OutputIndex struct {
 }
 
 func NewOutputIndex(input *InputIndex, option *OutputOptions, style *OutputStyle) *OutputIndex {
-	sorter := NewIndexSorter(option.sort)
+	sorter := NewIndexSorter(option, style)
 	outindex := sorter.SortIndex(input, style, option)
 	outindex.style = style
 	outindex.option = option
@@ -42,56 +44,142 @@ func (o *OutputIndex) Output(option *OutputOptions) {
 	writer = transform.NewWriter(writer, option.encoder)
 
 	fmt.Fprint(writer, o.style.preamble)
-	first_group := true
-	for _, group := range o.groups {
-		if group.items == nil {
+	o.writeNavbar(writer)
+	o.writeGroups(writer, o.groups, "", 0, true)
+	fmt.Fprint(writer, o.style.postamble)
+}
+
+// 在第一个分组前输出由各分组名组成的导航条，navbar_item 为空时不输出
+func (o *OutputIndex) writeNavbar(writer io.Writer) {
+	if o.style.navbar_item == "" || o.style.headings_flag == 0 {
+		return
+	}
+	fmt.Fprint(writer, o.style.navbar_prefix)
+	first := true
+	for i := range o.groups {
+		group := &o.groups[i]
+		format := o.style.navbar_item
+		if group.empty() {
+			// 空分组不作链接，只列出字母分组，navbar_empty_item 为空时不列出
+			format = o.style.navbar_empty_item
+			if format == "" || group.kind != GROUP_LETTER {
+				continue
+			}
+		}
+		if first {
+			first = false
+		} else {
+			fmt.Fprint(writer, o.style.navbar_delim)
+		}
+		fmt.Fprint(writer, o.expandGroupFormat(format, group, o.style.anchor_prefix+strconv.Itoa(i)))
+	}
+	fmt.Fprint(writer, o.style.navbar_suffix)
+}
+
+// 输出第 depth 层的各分组，first 表示前面没有同层的分组或上层分组的索引项
+// anchor 是上层分组的锚点名，各层分组的锚点名依次在后面加上分组的序号
+func (o *OutputIndex) writeGroups(writer io.Writer, groups []IndexGroup, anchor string, depth int, first bool) {
+	for i := range groups {
+		group := &groups[i]
+		prefix, suffix, skip := o.style.headingFormat(depth, group.kind)
+		if group.empty() && !o.showEmpty(group) {
 			continue
 		}
-		if first_group {
-			first_group = false
+		if first {
+			first = false
 		} else {
-			fmt.Fprint(writer, o.style.group_skip)
+			fmt.Fprint(writer, skip)
+		}
+		group_anchor := o.style.anchor_prefix + strconv.Itoa(i)
+		if depth > 0 {
+			group_anchor = anchor + "." + strconv.Itoa(i)
 		}
 		if o.style.headings_flag != 0 {
-			fmt.Fprintf(writer, "%s%s%s", o.style.heading_prefix, group.name, o.style.heading_suffix)
+			fmt.Fprint(writer, o.expandGroupFormat(prefix, group, group_anchor), group.name,
+				o.expandGroupFormat(suffix, group, group_anchor))
 		}
-		for i, item := range group.items {
-			// debug.Println(i, item)
-			// 如果修改一下 OutputStyle 的数据结构，容易改成任意层的索引
-			switch item.level {
-			case 0:
-				fmt.Fprintf(writer, "%s%s", o.style.item_0, item.text)
-				writePage(writer, 0, item.page, o.style)
-			case 1:
-				if last := group.items[i-1]; last.level == 0 {
-					if last.page != nil {
-						fmt.Fprint(writer, o.style.item_01)
-					} else {
-						fmt.Fprint(writer, o.style.item_x1)
-					}
+		if group.empty() {
+			fmt.Fprint(writer, o.style.empty_group_placeholder)
+			continue
+		}
+		o.writeItems(writer, group.items)
+		o.writeGroups(writer, group.subgroups, group_anchor, depth+1, group.items == nil)
+	}
+}
+
+// 判断是否输出空分组：empty_group_flag 为 1 时输出空的字母分组，为 2 时输出所有空分组
+func (o *OutputIndex) showEmpty(group *IndexGroup) bool {
+	switch o.style.empty_group_flag {
+	case 0:
+		return false
+	case 1:
+		return group.kind == GROUP_LETTER
+	default:
+		return true
+	}
+}
+
+// 展开分组名前后缀中的占位符：%{name} 为分组名，%{anchor} 为分组的锚点名，
+// %{kind} 为分组种类（symbols、numbers、letters 或 cjk），%{count} 为分组的项数
+func (o *OutputIndex) expandGroupFormat(format string, group *IndexGroup, anchor string) string {
+	if !strings.Contains(format, "%{") {
+		return format
+	}
+	return strings.NewReplacer(
+		"%{name}", group.name,
+		"%{anchor}", anchor,
+		"%{kind}", groupKindName(group.kind),
+		"%{count}", strconv.Itoa(group.count()),
+	).Replace(format)
+}
+
+// 输出一组索引项
+func (o *OutputIndex) writeItems(writer io.Writer, items []IndexItem) {
+	for i, item := range items {
+		// debug.Println(i, item)
+		// 如果修改一下 OutputStyle 的数据结构，容易改成任意层的索引
+		switch item.level {
+		case 0:
+			fmt.Fprintf(writer, "%s%s", o.style.item_0, item.text)
+			o.writeReading(writer, item)
+			writePage(writer, 0, item.page, o.style)
+		case 1:
+			if last := items[i-1]; last.level == 0 {
+				if last.page != nil {
+					fmt.Fprint(writer, o.style.item_01)
 				} else {
-					fmt.Fprint(writer, o.style.item_1)
+					fmt.Fprint(writer, o.style.item_x1)
 				}
-				fmt.Fprint(writer, item.text)
-				writePage(writer, 1, item.page, o.style)
-			case 2:
-				if last := group.items[i-1]; last.level == 1 {
-					if last.page != nil {
-						fmt.Fprint(writer, o.style.item_12)
-					} else {
-						fmt.Fprint(writer, o.style.item_x2)
-					}
+			} else {
+				fmt.Fprint(writer, o.style.item_1)
+			}
+			fmt.Fprint(writer, item.text)
+			o.writeReading(writer, item)
+			writePage(writer, 1, item.page, o.style)
+		case 2:
+			if last := items[i-1]; last.level == 1 {
+				if last.page != nil {
+					fmt.Fprint(writer, o.style.item_12)
 				} else {
-					fmt.Fprint(writer, o.style.item_2)
+					fmt.Fprint(writer, o.style.item_x2)
 				}
-				fmt.Fprint(writer, item.text)
-				writePage(writer, 2, item.page, o.style)
-			default:
-				log.Printf("索引项“%s”层次数过深，忽略此项\n", item.text)
+			} else {
+				fmt.Fprint(writer, o.style.item_2)
 			}
+			fmt.Fprint(writer, item.text)
+			o.writeReading(writer, item)
+			writePage(writer, 2, item.page, o.style)
+		default:
+			log.Printf("索引项“%s”层次数过深，忽略此项\n", item.text)
 		}
 	}
-	fmt.Fprint(writer, o.style.postamble)
+}
+
+// 在索引项文字后输出注音
+func (o *OutputIndex) writeReading(writer io.Writer, item IndexItem) {
+	if item.reading != "" {
+		fmt.Fprint(writer, o.style.reading_prefix, item.reading, o.style.reading_suffix)
+	}
 }
 
 func writePage(out io.Writer, level int, pageranges []PageRange, style *OutputStyle) {
@@ -118,16 +206,78 @@ func writePage(out io.Writer, level int, pageranges []PageRange, style *OutputSt
 }
 
 // 一个输出项目组
+// 有子分组时，items 是没有子分组名的项，在第一个子分组之前输出，以免接在前一子分组之后
 type IndexGroup struct {
-	name  string
-	items []IndexItem
+	name      string
+	kind      int // 分组种类，如 GROUP_LETTER
+	items     []IndexItem
+	subgroups []IndexGroup
+	dynamic   bool // 由 SubgroupCollator 得到的子分组，不参与稀疏分组的合并
+	// 分组名中编号前后的文字，如笔画分组“3 画”的后缀“ 画”，合并分组时只写一次
+	name_prefix, name_suffix string
 }
 
-// 一个输出项，包括级别、文字、一系列页码区间
+// 分组及其所有子分组中第 0 层索引项的个数
+func (group *IndexGroup) count() int {
+	n := 0
+	for _, item := range group.items {
+		if item.level == 0 {
+			n++
+		}
+	}
+	for i := range group.subgroups {
+		n += group.subgroups[i].count()
+	}
+	return n
+}
+
+// 判断分组及其所有子分组是否都没有索引项
+func (group *IndexGroup) empty() bool {
+	if group.items != nil {
+		return false
+	}
+	for i := range group.subgroups {
+		if !group.subgroups[i].empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// 向分组中添加一项，subgroup 为子分组名，空串表示没有子分组，这样的项放在第一个子分组之前
+// 各项按排序的次序添加，只与最后一个子分组同名时才加入其中，否则开始新的子分组；
+// 因此同名的子分组不连续时（如按整词排序时的音节）分别输出
+func (group *IndexGroup) add(subgroup string, item IndexItem) {
+	if subgroup == "" {
+		group.items = append(group.items, item)
+		return
+	}
+	group.appendSubgroup(IndexGroup{name: subgroup, kind: group.kind, items: []IndexItem{item}, dynamic: true})
+}
+
+// 在最后加入一个子分组，与最后一个子分组同名时合为一个
+func (group *IndexGroup) appendSubgroup(sub IndexGroup) {
+	if n := len(group.subgroups); n > 0 && group.subgroups[n-1].name == sub.name {
+		group.subgroups[n-1].merge(&sub)
+		return
+	}
+	group.subgroups = append(group.subgroups, sub)
+}
+
+// 把另一分组的各项与子分组接在此分组之后，没有子分组名的项接在此分组的同类项之后
+func (group *IndexGroup) merge(other *IndexGroup) {
+	group.items = append(group.items, other.items...)
+	for _, sub := range other.subgroups {
+		group.appendSubgroup(sub)
+	}
+}
+
+// 一个输出项，包括级别、文字、注音、一系列页码区间
 type IndexItem struct {
-	level int
-	text  string
-	page  []PageRange
+	level   int
+	text    string
+	reading string
+	page    []PageRange
 }
 
 // 用于输出的页码区间
@@ -140,6 +290,51 @@ func (p *PageRange) Diff() int {
 	return p.end.Diff(p.begin)
 }
 
+// 从区间中去掉与区间 other 重叠的页码，返回余下的零至两段
+// 两个区间的页码只有最后一级数字不同时才能相减，否则原样返回
+func (p PageRange) subtract(other PageRange) []PageRange {
+	diff, other_diff := p.Diff(), other.Diff()
+	if diff < 0 || diff == MaxInt || other_diff < 0 || other_diff == MaxInt {
+		return []PageRange{p}
+	}
+	if d := p.begin.Diff(other.begin); d < 0 || d == MaxInt {
+		return []PageRange{p}
+	}
+	last := len(p.begin.numbers) - 1
+	begin, end := p.begin.numbers[last].num, p.end.numbers[last].num
+	other_begin, other_end := other.begin.numbers[last].num, other.end.numbers[last].num
+	if other_end < begin || end < other_begin {
+		return []PageRange{p}
+	}
+	var rest []PageRange
+	if begin < other_begin {
+		rest = append(rest, PageRange{begin: p.begin, end: p.end.withLast(other_begin - 1)})
+	}
+	if other_end < end {
+		rest = append(rest, PageRange{begin: p.begin.withLast(other_end + 1), end: p.end})
+	}
+	return rest
+}
+
+// 按 range_abbrev 写出区间尾，如 123--125 写作 123--25
+// 只省略普通页码最后一级的阿拉伯数字；复合页码前面各级相同，且最后一级按规则省略了数字时，
+// 前面各级一并省略，否则写出完整的区间尾
+func (p *PageRange) abbrevEnd(rule string) string {
+	if rule == "none" || p.begin.class != nil || !p.begin.Compatible(p.end) || p.Diff() == MaxInt {
+		return p.end.String()
+	}
+	last := len(p.end.numbers) - 1
+	begin, end := p.begin.numbers[last], p.end.numbers[last]
+	if end.format != NUM_ARABIC || end.num <= begin.num {
+		return p.end.String()
+	}
+	abbrev := abbrevPageNumber(begin.num, end.num, rule)
+	if last > 0 && abbrev == end.String() {
+		return p.end.String()
+	}
+	return abbrev
+}
+
 // 输出页码区间
 func (p *PageRange) Write(out io.Writer, style *OutputStyle) {
 	var rangestr string
@@ -162,7 +357,7 @@ func (p *PageRange) Write(out io.Writer, style *OutputStyle) {
 		rangestr = p.begin.String() + style.suffix_mp
 	// 普通的区间
 	default:
-		rangestr = p.begin.String() + style.delim_r + p.end.String()
+		rangestr = p.begin.String() + style.delim_r + p.abbrevEnd(style.range_abbrev)
 	}
 	// encap 只看区间头，对不完全区间可能不总正确
 	if p.begin.encap == "" {
@@ -15,6 +15,7 @@ type Page struct {
 	compositor string
 	encap      string
 	rangetype  RangeType
+	class      *LocatorClass // 格式文件定义的页码类，nil 表示普通页码
 }
 
 // 按 p 生成一个与之输出类型相同的空页码
@@ -24,10 +25,22 @@ func (p *Page) Empty() *Page {
 		compositor: p.compositor,
 		encap:      p.encap,
 		rangetype:  PAGE_UNKNOWN,
+		class:      p.class,
 	}
 }
 
+// 复制页码 p，并把最后一级数字改为 num
+func (p *Page) withLast(num int) *Page {
+	page := *p
+	page.numbers = append([]PageNumber(nil), p.numbers...)
+	page.numbers[len(page.numbers)-1].num = num
+	return &page
+}
+
 func (p *Page) String() string {
+	if p.class != nil {
+		return p.class.format(p.numbers)
+	}
 	var page_str []string
 	for _, pn := range p.numbers {
 		page_str = append(page_str, pn.String())
@@ -36,9 +49,12 @@ func (p *Page) String() string {
 }
 
 // 判断两个页码是否类型一致
-// 两个页码类型一致指它们有相同多个类型相同的数字构成
+// 两个页码类型一致指它们属于同一页码类，有相同多个类型相同的数字构成，且分隔符相同
 func (page *Page) Compatible(other *Page) bool {
-	if len(page.numbers) != len(other.numbers) {
+	if page.class != other.class || len(page.numbers) != len(other.numbers) {
+		return false
+	}
+	if len(page.numbers) > 1 && page.compositor != other.compositor {
 		return false
 	}
 	for i := 0; i < len(page.numbers); i++ {
@@ -71,12 +87,28 @@ func (page *Page) Diff(other *Page) int {
 	return abs(page.numbers[depth-1].num - other.numbers[depth-1].num)
 }
 
+// 合并区间时允许的最大页码差：一般为 1，即连续的页码合并；
+// 页码类设置了不合并区间时为 0，只合并重复的页码
+func (page *Page) mergeGap() int {
+	if page.class != nil && !page.class.merge {
+		return 0
+	}
+	return 1
+}
+
 // 按字典序比较两个页码数字串的大小，返回负、零、正值
-// 不同类型的序关系由参数 precedence 给出，不一致的页码仍有大小关系
+// 不同页码类按其 precedence 排序，不同类型的序关系由参数 precedence 给出，不一致的页码仍有大小关系
 // 不比较页码的 encap、rangetype 信息
 func (page *Page) Cmp(other *Page, precedence map[NumFormat]int) int {
+	if cmp := compareLocatorClass(page.class, other.class); cmp != 0 {
+		return cmp
+	}
 	for i := 0; i < len(page.numbers) && i < len(other.numbers); i++ {
 		a, b := page.numbers[i], other.numbers[i]
+		// 前面的数字都相同时，分隔符不同的按分隔符排序，使同一分隔符的页码相邻
+		if i > 0 && page.compositor != other.compositor {
+			return strings.Compare(page.compositor, other.compositor)
+		}
 		if precedence[a.format] != precedence[b.format] {
 			return precedence[a.format] - precedence[b.format]
 		} else if a.num != b.num {
@@ -92,10 +124,16 @@ func (page *Page) Cmp(other *Page, precedence map[NumFormat]int) int {
 type PageNumber struct {
 	format NumFormat
 	num    int
+	cjk    CJKNumStyle // 汉字数字页码的写法
 }
 
 func (p PageNumber) String() string {
-	return p.format.Format(p.num)
+	switch p.format {
+	case NUM_CJK_LOWER, NUM_CJK_UPPER:
+		return formatCJKPage(p.num, p.format == NUM_CJK_UPPER, p.cjk)
+	default:
+		return p.format.Format(p.num)
+	}
 }
 
 type NumFormat int
@@ -107,42 +145,107 @@ const (
 	NUM_ROMAN_UPPER
 	NUM_ALPH_LOWER
 	NUM_ALPH_UPPER
+	NUM_CJK_LOWER    // 小写汉字数字，如“十二”
+	NUM_CJK_UPPER    // 大写汉字数字，如“拾贰”
+	NUM_UROMAN_LOWER // Unicode 小写罗马数字，如“ⅻ”
+	NUM_UROMAN_UPPER // Unicode 大写罗马数字，如“Ⅻ”
+	NUM_FULLWIDTH    // 全角阿拉伯数字，如“１２”
 )
 
-// 将字符串解析为一串页码数字
-func scanPage(token []rune, compositor string) ([]PageNumber, error) {
-	numstr_list := strings.Split(string(token), compositor)
-	var nums []PageNumber
-	for _, numstr := range numstr_list {
-		pn, err := scanNumber([]rune(numstr))
-		if err != nil {
-			return nil, err
+// 汉字数字页码的写法，读入页码时记录，输出时按原来的写法写出
+type CJKNumStyle int
+
+const (
+	CJK_NUM_UNIT     CJKNumStyle = iota // 带单位，开头的“一十”省略“一”，如“十二”“拾贰”
+	CJK_NUM_UNIT_ONE                    // 带单位，开头的“一十”不省略，如“一十二”“壹拾贰”
+	CJK_NUM_DIGITS                      // 不带单位的逐位写法，如“一二”“壹贰”
+)
+
+// page_precedence 中表示各数字格式的字母
+var numFormatLetters = map[rune]NumFormat{
+	'r': NUM_ROMAN_LOWER,
+	'n': NUM_ARABIC,
+	'a': NUM_ALPH_LOWER,
+	'R': NUM_ROMAN_UPPER,
+	'A': NUM_ALPH_UPPER,
+	'c': NUM_CJK_LOWER,
+	'C': NUM_CJK_UPPER,
+	'u': NUM_UROMAN_LOWER,
+	'U': NUM_UROMAN_UPPER,
+	'f': NUM_FULLWIDTH,
+}
+
+// 各数字格式的默认次序，page_precedence 中未列出的格式按此次序排在最后
+const defaultPagePrecedence = "rcunfaRCUA"
+
+// 将字符串解析为一串页码数字，返回页码数字与所用的分隔符
+// 依次尝试各个分隔符，使用第一个能把整个串解析为页码数字的；
+// 只有一个数字的页码总使用第一个分隔符，以便与其他单个数字的页码一致
+// alph 为真时拉丁字母组成的数字总按字母页码读入，见 scanNumber
+func scanPage(token []rune, compositors []string, alph bool) ([]PageNumber, string, error) {
+	for _, compositor := range compositors {
+		var nums []PageNumber
+		for _, numstr := range strings.Split(string(token), compositor) {
+			pn, err := scanNumber([]rune(numstr), alph)
+			if err != nil {
+				nums = nil
+				break
+			}
+			nums = append(nums, pn)
+		}
+		if len(nums) == 1 {
+			return nums, compositors[0], nil
+		} else if nums != nil {
+			return nums, compositor, nil
 		}
-		nums = append(nums, pn)
 	}
-	return nums, nil
+	return nil, "", ScanSyntaxError
 }
 
-// 读入数字，并粗略判断其格式
-func scanNumber(token []rune) (PageNumber, error) {
+// 读入数字，并按首字符判断其格式
+// 以罗马数字字母开头的串先按罗马数字读入，不是规范的罗马数字时再按字母页码读入；
+// alph 为真时拉丁字母组成的串总按字母页码读入
+func scanNumber(token []rune, alph bool) (PageNumber, error) {
 	if len(token) == 0 {
 		return PageNumber{}, ScanSyntaxError
 	}
-	if r := token[0]; unicode.IsDigit(r) {
+	if r := token[0]; '０' <= r && r <= '９' {
+		num, err := scanFullwidth(token)
+		return PageNumber{format: NUM_FULLWIDTH, num: num}, err
+	} else if unicode.IsDigit(r) {
 		num, err := scanArabic(token)
 		return PageNumber{format: NUM_ARABIC, num: num}, err
-	} else if romanLowerValue[r] != 0 {
-		num, err := scanRomanLower(token)
-		return PageNumber{format: NUM_ROMAN_LOWER, num: num}, err
-	} else if romanUpperValue[r] != 0 {
-		num, err := scanRomanUpper(token)
-		return PageNumber{format: NUM_ROMAN_UPPER, num: num}, err
+	} else if romanLowerValue[r] != 0 && !alph {
+		if num, err := scanRomanLower(token); err == nil {
+			return PageNumber{format: NUM_ROMAN_LOWER, num: num}, nil
+		}
+		num, err := scanAlphLower(token)
+		return PageNumber{format: NUM_ALPH_LOWER, num: num}, err
+	} else if romanUpperValue[r] != 0 && !alph {
+		if num, err := scanRomanUpper(token); err == nil {
+			return PageNumber{format: NUM_ROMAN_UPPER, num: num}, nil
+		}
+		num, err := scanAlphUpper(token)
+		return PageNumber{format: NUM_ALPH_UPPER, num: num}, err
 	} else if 'a' <= r && r <= 'z' {
 		num, err := scanAlphLower(token)
 		return PageNumber{format: NUM_ALPH_LOWER, num: num}, err
 	} else if 'A' <= r && r <= 'Z' {
 		num, err := scanAlphUpper(token)
 		return PageNumber{format: NUM_ALPH_UPPER, num: num}, err
+	} else if uromanLowerValue[r] != 0 {
+		num, err := scanRoman(token, uromanLowerValue)
+		return PageNumber{format: NUM_UROMAN_LOWER, num: num}, err
+	} else if uromanUpperValue[r] != 0 {
+		num, err := scanRoman(token, uromanUpperValue)
+		return PageNumber{format: NUM_UROMAN_UPPER, num: num}, err
+	} else if IsCJKNumRune(r) {
+		num, err := scanCJKPage(token)
+		format := NUM_CJK_LOWER
+		if strings.ContainsAny(string(token), cjkUpperPageRunes) {
+			format = NUM_CJK_UPPER
+		}
+		return PageNumber{format: format, num: num, cjk: cjkNumStyleOf(token)}, err
 	}
 	return PageNumber{}, ScanSyntaxError
 }
@@ -155,12 +258,133 @@ func scanArabic(token []rune) (int, error) {
 	return num, err
 }
 
+func scanFullwidth(token []rune) (int, error) {
+	num := 0
+	for _, r := range token {
+		if r < '０' || r > '９' || num > (MaxInt-9)/10 {
+			return 0, ScanSyntaxError
+		}
+		num = num*10 + int(r-'０')
+	}
+	return num, nil
+}
+
+// 按指定的格式读入数字
+func scanNumberAs(token []rune, format NumFormat) (int, error) {
+	switch format {
+	case NUM_ARABIC:
+		return scanArabic(token)
+	case NUM_ROMAN_LOWER:
+		return scanRomanLower(token)
+	case NUM_ROMAN_UPPER:
+		return scanRomanUpper(token)
+	case NUM_ALPH_LOWER:
+		return scanAlphLower(token)
+	case NUM_ALPH_UPPER:
+		return scanAlphUpper(token)
+	case NUM_CJK_LOWER, NUM_CJK_UPPER:
+		return scanCJKPage(token)
+	case NUM_UROMAN_LOWER:
+		return scanRoman(token, uromanLowerValue)
+	case NUM_UROMAN_UPPER:
+		return scanRoman(token, uromanUpperValue)
+	case NUM_FULLWIDTH:
+		return scanFullwidth(token)
+	default:
+		return 0, ScanSyntaxError
+	}
+}
+
+// 判断字符是否可以出现在某种格式的数字中
+func isNumFormatRune(format NumFormat, r rune) bool {
+	switch format {
+	case NUM_ARABIC:
+		return '0' <= r && r <= '9'
+	case NUM_ROMAN_LOWER:
+		return romanLowerValue[r] != 0
+	case NUM_ROMAN_UPPER:
+		return romanUpperValue[r] != 0
+	case NUM_ALPH_LOWER:
+		return 'a' <= r && r <= 'z'
+	case NUM_ALPH_UPPER:
+		return 'A' <= r && r <= 'Z'
+	case NUM_CJK_LOWER, NUM_CJK_UPPER:
+		return IsCJKNumRune(r)
+	case NUM_UROMAN_LOWER:
+		return uromanLowerValue[r] != 0
+	case NUM_UROMAN_UPPER:
+		return uromanUpperValue[r] != 0
+	case NUM_FULLWIDTH:
+		return '０' <= r && r <= '９'
+	default:
+		return false
+	}
+}
+
+// 只出现在大写汉字数字中的字符
+const cjkUpperPageRunes = "壹贰貳叁參肆伍陆陸柒捌玖拾佰仟"
+
+// 读入整个串都是汉字数字的页码，如“十二”“拾贰”“一二”
+func scanCJKPage(token []rune) (int, error) {
+	num, n := scanCJKNumber(token)
+	if n == 0 || n != len(token) {
+		return 0, ScanSyntaxError
+	}
+	value, ok := num.Int()
+	if !ok {
+		return 0, ScanSyntaxError
+	}
+	return value, nil
+}
+
+// 判断汉字数字页码的写法
+func cjkNumStyleOf(token []rune) CJKNumStyle {
+	for _, r := range token {
+		if cjkUnitValue[r] != 0 {
+			if len(token) >= 2 && cjkDigitValue[token[0]] == 1 && cjkUnitValue[token[1]] == 10 {
+				return CJK_NUM_UNIT_ONE
+			}
+			return CJK_NUM_UNIT
+		}
+	}
+	return CJK_NUM_DIGITS
+}
+
+// 按读入时的写法写出汉字数字页码
+func formatCJKPage(num int, upper bool, style CJKNumStyle) string {
+	switch style {
+	case CJK_NUM_DIGITS:
+		return FormatCJKDigits(uint64(num), upper)
+	case CJK_NUM_UNIT_ONE:
+		digits, units := cjkLowerDigits, cjkLowerUnits
+		if upper {
+			digits, units = cjkUpperDigits, cjkUpperUnits
+		}
+		s := []rune(FormatCJKNumber(uint64(num), upper))
+		if s[0] == units[0] {
+			s = append([]rune{digits[1]}, s...)
+		}
+		return string(s)
+	default:
+		return FormatCJKNumber(uint64(num), upper)
+	}
+}
+
 func scanRomanLower(token []rune) (int, error) {
-	return scanRoman(token, romanLowerValue)
+	return scanCanonicalRoman(token, romanLowerValue, false)
 }
 
 func scanRomanUpper(token []rune) (int, error) {
-	return scanRoman(token, romanUpperValue)
+	return scanCanonicalRoman(token, romanUpperValue, true)
+}
+
+// 读入拉丁字母的罗马数字，只接受规范写法，"ic" "iiii" 这样的串不是罗马数字
+func scanCanonicalRoman(token []rune, romantable map[rune]int, upper bool) (int, error) {
+	num, err := scanRoman(token, romantable)
+	if err == nil && romanNumString(num, upper) != string(token) {
+		return 0, ScanSyntaxError
+	}
+	return num, err
 }
 
 func scanRoman(token []rune, romantable map[rune]int) (int, error) {
@@ -185,18 +409,34 @@ var romanUpperValue = map[rune]int{
 	'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000,
 }
 
+// Unicode 罗马数字（U+2160 至 U+217F），Ⅰ 至 Ⅻ 各是一个字符
+var uromanLowerValue = map[rune]int{
+	'ⅰ': 1, 'ⅱ': 2, 'ⅲ': 3, 'ⅳ': 4, 'ⅴ': 5, 'ⅵ': 6, 'ⅶ': 7, 'ⅷ': 8, 'ⅸ': 9, 'ⅹ': 10, 'ⅺ': 11, 'ⅻ': 12,
+	'ⅼ': 50, 'ⅽ': 100, 'ⅾ': 500, 'ⅿ': 1000,
+}
+var uromanUpperValue = map[rune]int{
+	'Ⅰ': 1, 'Ⅱ': 2, 'Ⅲ': 3, 'Ⅳ': 4, 'Ⅴ': 5, 'Ⅵ': 6, 'Ⅶ': 7, 'Ⅷ': 8, 'Ⅸ': 9, 'Ⅹ': 10, 'Ⅺ': 11, 'Ⅻ': 12,
+	'Ⅼ': 50, 'Ⅽ': 100, 'Ⅾ': 500, 'Ⅿ': 1000,
+}
+
 func scanAlphLower(token []rune) (int, error) {
-	if len(token) != 1 || token[0] < 'a' || token[0] > 'z' {
-		return 0, ScanSyntaxError
-	}
-	return int(token[0]-'a') + 1, nil
+	return scanAlph(token, 'a')
 }
 
 func scanAlphUpper(token []rune) (int, error) {
-	if len(token) != 1 || token[0] < 'A' || token[0] > 'Z' {
-		return 0, ScanSyntaxError
+	return scanAlph(token, 'A')
+}
+
+// 读入字母页码，a 至 z 之后是 aa、ab 等，即以 26 个字母为数码的双射记数法
+func scanAlph(token []rune, first rune) (int, error) {
+	num := 0
+	for _, r := range token {
+		if r < first || r > first+25 || num > (MaxInt-26)/26 {
+			return 0, ScanSyntaxError
+		}
+		num = num*26 + int(r-first) + 1
 	}
-	return int(token[0]-'A') + 1, nil
+	return num, nil
 }
 
 // 按格式输出数字
@@ -207,13 +447,23 @@ func (numfmt NumFormat) Format(num int) string {
 	case NUM_ARABIC:
 		return fmt.Sprint(num)
 	case NUM_ALPH_LOWER:
-		return string('a' + num)
+		return alphNumString(num, 'a')
 	case NUM_ALPH_UPPER:
-		return string('A' + num)
+		return alphNumString(num, 'A')
 	case NUM_ROMAN_LOWER:
 		return romanNumString(num, false)
 	case NUM_ROMAN_UPPER:
 		return romanNumString(num, true)
+	case NUM_CJK_LOWER:
+		return FormatNumber(num, "chinese")
+	case NUM_CJK_UPPER:
+		return FormatNumber(num, "chinese_upper")
+	case NUM_UROMAN_LOWER:
+		return uromanNumString(num, false)
+	case NUM_UROMAN_UPPER:
+		return uromanNumString(num, true)
+	case NUM_FULLWIDTH:
+		return FormatNumber(num, "fullwidth")
 	default:
 		panic("数字格式错误")
 	}
@@ -244,3 +494,80 @@ func romanNumString(num int, upper bool) string {
 		return string(numstr)
 	}
 }
+
+// 写出字母页码，是 scanAlph 的逆变换
+func alphNumString(num int, first rune) string {
+	var numstr []rune
+	for num > 0 {
+		num--
+		numstr = append([]rune{first + rune(num%26)}, numstr...)
+		num /= 26
+	}
+	return string(numstr)
+}
+
+// 写出 Unicode 罗马数字：十以上的部分逐个字母写出，个位用 Ⅰ 至 Ⅸ 一个字符，
+// 不超过 12 的数整个用一个字符，如 13 写作“ⅩⅢ”，12 写作“Ⅻ”
+func uromanNumString(num int, upper bool) string {
+	if num < 1 {
+		return ""
+	}
+	letters, ones := []rune("IVXLCDM"), []rune("ⅠⅡⅢⅣⅤⅥⅦⅧⅨⅩⅪⅫ")
+	uletters := []rune("ⅠⅤⅩⅬⅭⅮⅯ")
+	if !upper {
+		ones = []rune("ⅰⅱⅲⅳⅴⅵⅶⅷⅸⅹⅺⅻ")
+		uletters = []rune("ⅰⅴⅹⅼⅽⅾⅿ")
+	}
+	if num <= 12 {
+		return string(ones[num-1])
+	}
+	var numstr []rune
+	for _, r := range romanNumString(num-num%10, true) {
+		numstr = append(numstr, uletters[strings.IndexRune(string(letters), r)])
+	}
+	if num%10 != 0 {
+		numstr = append(numstr, ones[num%10-1])
+	}
+	return string(numstr)
+}
+
+// 按省略规则写出区间尾的数字 end，begin 是区间头的数字，end 大于 begin：
+// minimal 只保留与区间头不同的数字，如 123--5；
+// chicago 按《芝加哥手册》，区间头小于 100 或是 100 的倍数时不省略，
+// 末两位是 01 至 09 时只保留不同的数字，否则至少保留两位，如 101--8、321--28、1496--500；
+// two-digit 区间头小于 100 时不省略，否则至少保留两位，如 123--25、103--08
+// 区间尾位数比区间头多时都不省略
+func abbrevPageNumber(begin, end int, rule string) string {
+	b, e := strconv.Itoa(begin), strconv.Itoa(end)
+	if len(b) != len(e) || (begin < 100 && rule != "minimal") {
+		return e
+	}
+	common := 0
+	for common < len(e)-1 && b[common] == e[common] {
+		common++
+	}
+	keep := len(e) - common
+	switch rule {
+	case "chicago":
+		if begin%100 == 0 {
+			return e
+		} else if begin%100 >= 10 && keep < 2 {
+			keep = 2
+		}
+	case "two-digit":
+		if keep < 2 {
+			keep = 2
+		}
+	}
+	return e[len(e)-keep:]
+}
+
+// 判断是否是可用的区间省略规则
+func isRangeAbbrev(rule string) bool {
+	switch rule {
+	case "none", "minimal", "chicago", "two-digit":
+		return true
+	default:
+		return false
+	}
+}
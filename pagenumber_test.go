@@ -0,0 +1,163 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestScanNumber(t *testing.T) {
+	tests := []struct {
+		input  string
+		format NumFormat
+		num    int
+	}{
+		{"12", NUM_ARABIC, 12},
+		{"xiv", NUM_ROMAN_LOWER, 14},
+		{"MCMXC", NUM_ROMAN_UPPER, 1990},
+		{"b", NUM_ALPH_LOWER, 2},
+		{"z", NUM_ALPH_LOWER, 26},
+		{"aa", NUM_ALPH_LOWER, 27},
+		{"ab", NUM_ALPH_LOWER, 28},
+		{"ca", NUM_ALPH_LOWER, 79},
+		{"BA", NUM_ALPH_UPPER, 53},
+		{"十二", NUM_CJK_LOWER, 12},
+		{"一百零五", NUM_CJK_LOWER, 105},
+		{"拾贰", NUM_CJK_UPPER, 12},
+		{"一二", NUM_CJK_LOWER, 12},
+		{"一十二", NUM_CJK_LOWER, 12},
+		{"二〇一九", NUM_CJK_LOWER, 2019},
+		{"壹拾贰", NUM_CJK_UPPER, 12},
+		{"壹零伍", NUM_CJK_UPPER, 105},
+		{"Ⅻ", NUM_UROMAN_UPPER, 12},
+		{"ⅩⅢ", NUM_UROMAN_UPPER, 13},
+		{"ⅹⅼ", NUM_UROMAN_LOWER, 40},
+		{"１２", NUM_FULLWIDTH, 12},
+		// 规范的罗马数字按罗马数字读入，其他按字母页码读入
+		{"cc", NUM_ROMAN_LOWER, 200},
+		{"cd", NUM_ROMAN_LOWER, 400},
+		{"ic", NUM_ALPH_LOWER, 237},
+		{"iiii", NUM_ALPH_LOWER, 164511},
+		{"VV", NUM_ALPH_UPPER, 594},
+	}
+	for _, test := range tests {
+		pn, err := scanNumber([]rune(test.input), false)
+		if err != nil || pn.format != test.format || pn.num != test.num {
+			t.Errorf("scanNumber(%q) = %v, %v, want %v", test.input, pn, err, PageNumber{format: test.format, num: test.num})
+			continue
+		}
+		if s := pn.String(); s != test.input {
+			t.Errorf("PageNumber%v.String() = %q, want %q", pn, s, test.input)
+		}
+	}
+	for _, input := range []string{"", "a1", "十二页", "１2"} {
+		if pn, err := scanNumber([]rune(input), false); err == nil {
+			t.Errorf("scanNumber(%q) = %v, want error", input, pn)
+		}
+	}
+	// page_alph_flag 非零时拉丁字母总按字母页码读入
+	for input, want := range map[string]PageNumber{
+		"cc":  {format: NUM_ALPH_LOWER, num: 81},
+		"cd":  {format: NUM_ALPH_LOWER, num: 82},
+		"i":   {format: NUM_ALPH_LOWER, num: 9},
+		"XIV": {format: NUM_ALPH_UPPER, num: 16480},
+	} {
+		if pn, err := scanNumber([]rune(input), true); err != nil || pn != want {
+			t.Errorf("scanNumber(%q, true) = %v, %v, want %v", input, pn, err, want)
+		}
+	}
+}
+
+func TestParsePagePrecedence(t *testing.T) {
+	order := parsePagePrecedence("nr")
+	if !(order[NUM_ARABIC] < order[NUM_ROMAN_LOWER] && order[NUM_ROMAN_LOWER] < order[NUM_CJK_LOWER] &&
+		order[NUM_CJK_LOWER] < order[NUM_ALPH_UPPER]) {
+		t.Errorf("parsePagePrecedence(\"nr\") = %v", order)
+	}
+}
+
+func TestScanPageCompositors(t *testing.T) {
+	compositors := []string{"-", "."}
+	tests := []struct {
+		input      string
+		compositor string
+		count      int
+	}{
+		{"3-12", "-", 2},
+		{"A.4", ".", 2},
+		{"12", "-", 1},
+		{"1-2-3", "-", 3},
+	}
+	for _, test := range tests {
+		nums, compositor, err := scanPage([]rune(test.input), compositors, false)
+		if err != nil || compositor != test.compositor || len(nums) != test.count {
+			t.Errorf("scanPage(%q) = %v, %q, %v", test.input, nums, compositor, err)
+		}
+	}
+	if _, _, err := scanPage([]rune("1.2-3"), compositors, false); err == nil {
+		t.Errorf("scanPage(%q) should fail", "1.2-3")
+	}
+}
+
+func TestAbbrevPageNumber(t *testing.T) {
+	tests := []struct {
+		begin, end int
+		rule       string
+		want       string
+	}{
+		{123, 125, "minimal", "5"},
+		{71, 72, "minimal", "2"},
+		{96, 117, "minimal", "117"},
+		{3, 10, "chicago", "10"},
+		{71, 72, "chicago", "72"},
+		{100, 104, "chicago", "104"},
+		{1100, 1113, "chicago", "1113"},
+		{101, 108, "chicago", "8"},
+		{808, 833, "chicago", "33"},
+		{1103, 1104, "chicago", "4"},
+		{321, 328, "chicago", "28"},
+		{498, 532, "chicago", "532"},
+		{1087, 1089, "chicago", "89"},
+		{1496, 1500, "chicago", "500"},
+		{11564, 11615, "chicago", "615"},
+		{12991, 13001, "chicago", "3001"},
+		{123, 125, "two-digit", "25"},
+		{103, 108, "two-digit", "08"},
+		{923, 1003, "two-digit", "1003"},
+	}
+	for _, test := range tests {
+		if got := abbrevPageNumber(test.begin, test.end, test.rule); got != test.want {
+			t.Errorf("abbrevPageNumber(%d, %d, %q) = %q, want %q", test.begin, test.end, test.rule, got, test.want)
+		}
+	}
+}
+
+func TestAbbrevEnd(t *testing.T) {
+	compositors := []string{"-"}
+	tests := []struct {
+		begin, end string
+		rule       string
+		want       string
+	}{
+		{"123", "125", "chicago", "25"},
+		{"3-5", "3-9", "chicago", "3-9"},
+		{"3-5", "3-9", "minimal", "3-9"},
+		{"3-112", "3-115", "chicago", "15"},
+		{"3-112", "3-115", "none", "3-115"},
+		{"2-112", "3-115", "chicago", "3-115"},
+	}
+	for _, test := range tests {
+		var r PageRange
+		for _, p := range []struct {
+			page **Page
+			str  string
+		}{{&r.begin, test.begin}, {&r.end, test.end}} {
+			nums, compositor, err := scanPage([]rune(p.str), compositors, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			*p.page = &Page{numbers: nums, compositor: compositor}
+		}
+		if got := r.abbrevEnd(test.rule); got != test.want {
+			t.Errorf("abbrevEnd(%s--%s, %q) = %q, want %q", test.begin, test.end, test.rule, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 带声调的拼音字母，依次为一至四声，0 表示没有预组字符
+var toneMarks = map[rune][4]rune{
+	'a': {'ā', 'á', 'ǎ', 'à'},
+	'o': {'ō', 'ó', 'ǒ', 'ò'},
+	'e': {'ē', 'é', 'ě', 'è'},
+	'i': {'ī', 'í', 'ǐ', 'ì'},
+	'u': {'ū', 'ú', 'ǔ', 'ù'},
+	'v': {'ǖ', 'ǘ', 'ǚ', 'ǜ'},
+	'n': {0, 'ń', 'ň', 'ǹ'},
+	'm': {0, 'ḿ', 0, 0},
+}
+
+// 没有预组字符时使用的组合用声调符号
+var combiningToneMarks = [4]rune{'\u0304', '\u0301', '\u030c', '\u0300'}
+
+// 把无声调的拼音加数字声调转换为带声调符号的拼音，是 CJK/maketables.go 中
+// NumberedPinyin 的逆变换。如 lao3 转换为 lǎo，lv2 转换为 lǘ，轻声 ma5 转换为 ma
+// 声调标在 a、e 上，ou 标在 o 上，其余标在最后一个元音上；没有元音的（如 n2、m2）标在辅音上
+func TonedPinyin(numbered string) string {
+	if numbered == "" {
+		return ""
+	}
+	// 数字拼音只含 ASCII 字符，可以按字节处理
+	syllable := strings.TrimRight(numbered, "0123456789")
+	tone := 5
+	if last := numbered[len(numbered)-1]; '0' <= last && last <= '9' {
+		tone = int(last - '0')
+	}
+	// 找标声调的位置
+	mark := strings.IndexAny(syllable, "ae")
+	if mark < 0 {
+		mark = strings.Index(syllable, "ou")
+	}
+	if mark < 0 {
+		mark = strings.LastIndexAny(syllable, "iouv")
+	}
+	if mark < 0 {
+		// 没有元音，如“嗯”n2、“呒”m2
+		mark = 0
+	}
+	var toned []rune
+	for i, r := range syllable {
+		switch {
+		case i == mark && 1 <= tone && tone <= 4:
+			if toneMarks[r][tone-1] != 0 {
+				toned = append(toned, toneMarks[r][tone-1])
+			} else {
+				toned = append(toned, r, combiningToneMarks[tone-1])
+			}
+		case r == 'v':
+			toned = append(toned, 'ü')
+		default:
+			toned = append(toned, r)
+		}
+	}
+	return string(toned)
+}
+
+// 去掉数字声调的拼音，ü 仍写作 ü，如 lv3 转换为 lü
+func PlainPinyin(numbered string) string {
+	plain := strings.TrimRight(numbered, "0123456789")
+	return strings.Replace(plain, "v", "ü", -1)
+}
+
+// 把各音节的数字拼音连成注音，format 为注音格式：
+// toned 为带声调符号的拼音，各音节连写，a、o、e 开头的音节前加隔音符号“'”，如 xi1an1 作 Xī'ān；
+// numbered 为数字拼音，各音节以空格分隔。capital 为真时首字母大写
+func FormatReading(syllables []string, format string, capital bool) string {
+	var reading []string
+	for i, syllable := range syllables {
+		switch format {
+		case "numbered":
+			if i > 0 {
+				reading = append(reading, " ")
+			}
+			reading = append(reading, syllable)
+		default:
+			if i > 0 && syllable != "" && strings.IndexByte("aoe", syllable[0]) >= 0 {
+				reading = append(reading, "'")
+			}
+			reading = append(reading, TonedPinyin(syllable))
+		}
+	}
+	result := strings.Join(reading, "")
+	if capital && result != "" {
+		first, size := utf8.DecodeRuneInString(result)
+		result = string(unicode.ToUpper(first)) + result[size:]
+	}
+	return result
+}
+
+// 判断是否是可用的注音格式
+func isReadingFormat(format string) bool {
+	return format == "toned" || format == "numbered"
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTonedPinyin(t *testing.T) {
+	tests := map[string]string{
+		"lao3":    "lǎo",
+		"lv2":     "lǘ",
+		"lve4":    "lüè",
+		"ma5":     "ma",
+		"gou3":    "gǒu",
+		"gui4":    "guì",
+		"liu2":    "liú",
+		"zhuang1": "zhuāng",
+		"n2":      "ń",
+		"m4":      "m\u0300",
+		"er2":     "ér",
+		"":        "",
+	}
+	for numbered, want := range tests {
+		if got := TonedPinyin(numbered); got != want {
+			t.Errorf("TonedPinyin(%q) = %q, want %q", numbered, got, want)
+		}
+	}
+}
+
+func TestFormatReading(t *testing.T) {
+	tests := []struct {
+		syllables []string
+		format    string
+		capital   bool
+		want      string
+	}{
+		{[]string{"chong2", "qing4"}, "toned", true, "Chóngqìng"},
+		{[]string{"xi1", "an1"}, "toned", true, "Xī'ān"},
+		{[]string{"tian1", "e2"}, "toned", false, "tiān'é"},
+		{[]string{"lv4", "se4"}, "numbered", false, "lv4 se4"},
+		{nil, "toned", true, ""},
+	}
+	for _, test := range tests {
+		if got := FormatReading(test.syllables, test.format, test.capital); got != test.want {
+			t.Errorf("FormatReading(%q, %q, %v) = %q, want %q",
+				test.syllables, test.format, test.capital, got, test.want)
+		}
+	}
+}
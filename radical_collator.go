@@ -9,11 +9,41 @@ import (
 )
 
 // 汉字按部首-除部首笔画数排序，汉字按部首分组排在英文字母组后面
-type RadicalIndexCollator struct{}
+type RadicalIndexCollator struct {
+	gf     bool // 使用《汉字部首表》的 201 部首，而不是康熙字典的 214 部首
+	nested bool // 部首分组放在 cjk_heading 上层分组之下
+}
+
+// 部首的个数
+func (c RadicalIndexCollator) maxRadical() int {
+	if c.gf {
+		return CJK.MAX_RADICAL201
+	}
+	return CJK.MAX_RADICAL
+}
+
+// 取得字符的部首与除部首笔画数
+// 使用 201 部首时，部首字本身和对照表中逐字列出的字按所列归部；其他字的康熙字典部首换为
+// 对应的主部首，归入笔画数不同的部首时（如“二”部归入“一”部），除部首笔画数加上两部首的
+// 笔画数之差
+func (c RadicalIndexCollator) radicalStroke(r rune) CJK.RadicalStroke {
+	if c.gf {
+		if rs, ok := CJK.CharRadicals201[r]; ok {
+			return rs
+		}
+	}
+	rs := CJK.RadicalStrokes[r]
+	if rs == "" || !c.gf {
+		return rs
+	}
+	kangxi := rs.Radical()
+	stroke := rs.Stroke() + CJK.KangxiStrokeOffset201[kangxi]
+	return CJK.RadicalStroke([]byte{byte(CJK.KangxiToRadical201[kangxi]), byte(stroke)}) + rs[2:]
+}
 
-func (_ RadicalIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
+func (c RadicalIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
 	// 分组：符号、数字、字母 A..Z
-	groups := make([]IndexGroup, 2+26+CJK.MAX_RADICAL)
+	groups := make([]IndexGroup, 2+26+c.maxRadical())
 	if style.headings_flag > 0 {
 		groups[0].name = style.symhead_positive
 		groups[1].name = style.numhead_positive
@@ -29,6 +59,17 @@ func (_ RadicalIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
 			i++
 		}
 	}
+	if c.gf {
+		for r, i := 1, 2+26; r < CJK.MAX_RADICAL201+1; r++ {
+			radicalName := string(CJK.Radicals201[r].Origin)
+			if CJK.Radicals201[r].Variants != "" && style.radical_simplified_flag != 0 {
+				radicalName += style.radical_simplified_prefix + CJK.Radicals201[r].Variants + style.radical_simplified_suffix
+			}
+			groups[i].name = style.radical_prefix + radicalName + style.radical_suffix
+			i++
+		}
+		return nestCJKGroups(groups, c.nested, style)
+	}
 	for r, i := 1, 2+26; r < CJK.MAX_RADICAL+1; r++ {
 		var radicalName string
 		if CJK.Radicals[r].Simplified != 0 && style.radical_simplified_flag != 0 {
@@ -40,30 +81,49 @@ func (_ RadicalIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
 		groups[i].name = style.radical_prefix + radicalName + style.radical_suffix
 		i++
 	}
-	return groups
+	return nestCJKGroups(groups, c.nested, style)
 }
 
 // 取得分组
-func (_ RadicalIndexCollator) Group(entry *IndexEntry) int {
+func (c RadicalIndexCollator) Group(entry *IndexEntry) []int {
 	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
 	first = unicode.ToLower(first)
+	rs := c.radicalStroke(first)
 	switch {
 	case IsNumString(entry.level[0].key):
-		return 1
+		return c.NumberGroup()
 	case 'a' <= first && first <= 'z':
-		return 2 + int(first) - 'a'
-	case CJK.RadicalStrokes[first] != "":
+		return []int{2 + int(first) - 'a'}
+	case rs != "":
 		// 首字部首
-		return 2 + 26 + (CJK.RadicalStrokes[first].Radical() - 1)
+		return cjkGroupPath(rs.Radical()-1, c.nested)
 	default:
 		// 符号组
-		return 0
+		return []int{0}
+	}
+}
+
+// 数字分组的路径
+func (_ RadicalIndexCollator) NumberGroup() []int {
+	return []int{1}
+}
+
+// 取得子分组：部首分组内按首字的除部首笔画数分组
+func (c RadicalIndexCollator) Subgroup(entry *IndexEntry, style *OutputStyle) string {
+	if style.stroke_subheading_flag == 0 {
+		return ""
+	}
+	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
+	rs := c.radicalStroke(first)
+	if rs == "" {
+		return ""
 	}
+	return style.stroke_subheading_prefix + FormatNumber(rs.Stroke(), style.heading_number_format) + style.stroke_subheading_suffix
 }
 
 // 按汉字部首、除部首笔画数序比较两个字符大小
-func (_ RadicalIndexCollator) RuneCmp(a, b rune) int {
-	a_rs, b_rs := CJK.RadicalStrokes[a], CJK.RadicalStrokes[b]
+func (c RadicalIndexCollator) RuneCmp(a, b rune) int {
+	a_rs, b_rs := c.radicalStroke(a), c.radicalStroke(b)
 	switch {
 	case a_rs == "" && b_rs == "":
 		return RuneCmpIgnoreCases(a, b)
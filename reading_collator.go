@@ -8,46 +8,125 @@ import (
 )
 
 // 汉字按拼音排序，按拼音首字母与英文一起分组
-type ReadingIndexCollator struct{}
+type ReadingIndexCollator struct {
+	// 整词比较：先比较整个串的无声调拼音，再比较声调，最后逐字比较
+	word bool
+	// 汉字按拼音拼写与拉丁字母混合比较，原文字种只在拼写相同时区分先后
+	interleave bool
+	// 汉字数字按数值比较
+	cjk_number bool
+	// 所有索引项都按人名处理，首字按姓氏读音，复姓作为整体
+	surname bool
+	// 汉字按拼音首字母单独分组，排在西文字母分组之后
+	separate bool
+	// 单独的汉字分组放在 cjk_heading 上层分组之下
+	nested bool
+}
 
-func (_ ReadingIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
-	// 分组：符号、数字、字母 A..Z
+func (c ReadingIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
+	// 分组：符号、数字、字母 A..Z，单独分组时还有汉字 A..Z
 	groups := make([]IndexGroup, 2+26)
+	if c.separate {
+		groups = make([]IndexGroup, 2+26+26)
+	}
 	if style.headings_flag > 0 {
 		groups[0].name = style.symhead_positive
 		groups[1].name = style.numhead_positive
-		for alph, i := 'A', 2; alph <= 'Z'; alph++ {
-			groups[i].name = string(alph)
-			i++
+		for i := 2; i < len(groups); i++ {
+			groups[i].name = string(rune('A' + (i-2)%26))
 		}
 	} else if style.headings_flag < 0 {
 		groups[0].name = style.symhead_negative
 		groups[1].name = style.numhead_negative
-		for alph, i := 'a', 2; alph <= 'z'; alph++ {
-			groups[i].name = string(alph)
-			i++
+		for i := 2; i < len(groups); i++ {
+			groups[i].name = string(rune('a' + (i-2)%26))
 		}
 	}
-	return groups
+	return nestCJKGroups(groups, c.nested, style)
 }
 
 // 取得分组
-func (_ ReadingIndexCollator) Group(entry *IndexEntry) int {
+func (c ReadingIndexCollator) Group(entry *IndexEntry) []int {
 	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
 	first = unicode.ToLower(first)
+	surname, _ := surnameOf(entry.level[0].key)
 	switch {
 	case IsNumString(entry.level[0].key):
-		return 1
+		return c.NumberGroup()
 	case 'a' <= first && first <= 'z':
-		return 2 + int(first) - 'a'
+		return []int{2 + int(first) - 'a'}
+	case (c.surname || entry.level[0].name) && surname != nil:
+		// 人名按姓氏读音的首字母分组
+		return c.cjkLetterGroup(int(surname[0][0]))
 	case CJK.Readings[first] != "":
 		// 拼音首字母
 		reading_first := int(CJK.Readings[first][0])
-		return 2 + reading_first - 'a'
+		return c.cjkLetterGroup(reading_first)
 	default:
 		// 符号组
-		return 0
+		return []int{0}
+	}
+}
+
+// 数字分组的路径
+func (_ ReadingIndexCollator) NumberGroup() []int {
+	return []int{1}
+}
+
+// 汉字拼音首字母对应的分组
+func (c ReadingIndexCollator) cjkLetterGroup(letter int) []int {
+	if c.separate {
+		return cjkGroupPath(letter-'a', c.nested)
+	}
+	return []int{2 + letter - 'a'}
+}
+
+// 取得子分组：字母分组内按首字的音节分组
+// reading_subheading_flag 为 1 时子分组名是带声调的音节，为 2 时是不带声调的音节
+func (c ReadingIndexCollator) Subgroup(entry *IndexEntry, style *OutputStyle) string {
+	if style.reading_subheading_flag == 0 {
+		return ""
+	}
+	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
+	reading := CJK.Readings[first]
+	if c.surname || entry.level[0].name {
+		if surname, _ := surnameOf(entry.level[0].key); surname != nil {
+			reading = surname[0]
+		}
+	}
+	if reading == "" {
+		return ""
+	}
+	if style.reading_subheading_flag == 2 {
+		reading = PlainPinyin(reading)
+	} else {
+		reading = TonedPinyin(reading)
+	}
+	return style.reading_subheading_prefix + reading + style.reading_subheading_suffix
+}
+
+// 取得串中各汉字的数字拼音，用于输出注音；人名的姓氏按姓氏读音
+// 串中有汉字以外的字符（空白除外）时返回 nil
+func (c ReadingIndexCollator) Readings(s string, name bool) []string {
+	var readings []string
+	token := []rune(s)
+	if c.surname || name {
+		if surname, n := surnameOf(s); surname != nil {
+			readings = append(readings, surname...)
+			token = token[n:]
+		}
 	}
+	for _, r := range token {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		reading := CJK.Readings[r]
+		if reading == "" {
+			return nil
+		}
+		readings = append(readings, reading)
+	}
+	return readings
 }
 
 // 按汉字读音比较两个字符，读音相同的，内码序
@@ -69,6 +148,119 @@ func (_ ReadingIndexCollator) RuneCmp(a, b rune) int {
 	}
 }
 
+// 按整词比较两个串，实现 StringCollator
+// 次序同《现代汉语词典》：先比较整串的无声调拼音（ü 作 u），再区分 u 与 ü，然后比较声调
+// 混合拉丁字母比较时，只比较拼写，其余交由逐字比较；逐字比较的模式下总返回 0
+func (c ReadingIndexCollator) StringCmp(a, b string) int {
+	if !c.word && !c.interleave {
+		return 0
+	}
+	akey, bkey := c.makeReadingKey(a), c.makeReadingKey(b)
+	if cmp := compareInts(akey.spell, bkey.spell); cmp != 0 {
+		return cmp
+	}
+	if !c.word {
+		return 0
+	}
+	if cmp := compareInts(akey.letters, bkey.letters); cmp != 0 {
+		return cmp
+	}
+	return compareInts(akey.tones, bkey.tones)
+}
+
+// 按人名比较两个串，实现 NameCollator
+// 人名的姓氏按姓氏读音比较，复姓作为一个整体；读音相同的按姓氏用字的内码比较
+// 两个串都不是人名时总返回 0
+func (c ReadingIndexCollator) NameCmp(a, b string, a_name, b_name bool) int {
+	a_name, b_name = a_name || c.surname, b_name || c.surname
+	if !a_name && !b_name {
+		return 0
+	}
+	a_surname, a_len := c.firstReadings(a, a_name)
+	b_surname, b_len := c.firstReadings(b, b_name)
+	for i := range a_surname {
+		switch {
+		case i >= len(b_surname):
+			return 1
+		case a_surname[i] < b_surname[i]:
+			return -1
+		case a_surname[i] > b_surname[i]:
+			return 1
+		}
+	}
+	if len(a_surname) < len(b_surname) {
+		return -1
+	}
+	a_rune, b_rune := []rune(a), []rune(b)
+	for i := 0; i < a_len && i < b_len; i++ {
+		if a_rune[i] != b_rune[i] {
+			return int(a_rune[i] - b_rune[i])
+		}
+	}
+	return a_len - b_len
+}
+
+// 取得串开头的读音与字数：人名取姓氏，否则取首字
+func (_ ReadingIndexCollator) firstReadings(s string, name bool) ([]string, int) {
+	if name {
+		return surnameOf(s)
+	}
+	first, _ := utf8.DecodeRuneInString(s)
+	if reading := CJK.Readings[first]; reading != "" {
+		return []string{reading}, 1
+	}
+	return nil, 0
+}
+
+// 拼音整词比较使用的排序键
+type readingKey struct {
+	spell   []int // 无声调拼音，ü 记作 u；非汉字字符忽略大小写
+	letters []int // 无声调拼音，ü 记作 v，用以区分 lu 与 lü
+	tones   []int // 各汉字的声调
+}
+
+// 非汉字字符排在所有汉字拼音字母之前
+const readingLetterBase = 0x110000
+
+func (c ReadingIndexCollator) makeReadingKey(s string) readingKey {
+	var key readingKey
+	base := readingLetterBase
+	if c.interleave {
+		// 拼音字母与拉丁字母同等比较
+		base = 0
+	}
+	token := []rune(s)
+	for i := 0; i < len(token); i++ {
+		r := token[i]
+		if num, n := scanNumeral(token[i:], c.cjk_number); n > 0 {
+			// 连续的数字按数值比较
+			numkey := num.sortKey()
+			key.spell = append(key.spell, numkey...)
+			key.letters = append(key.letters, numkey...)
+			i += n - 1
+			continue
+		}
+		reading := CJK.Readings[r]
+		if reading == "" {
+			lower := int(unicode.ToLower(r))
+			key.spell = append(key.spell, lower)
+			key.letters = append(key.letters, lower)
+			continue
+		}
+		// 读音形如 lv3，末尾是声调
+		syllable, tone := reading[:len(reading)-1], int(reading[len(reading)-1]-'0')
+		for _, letter := range syllable {
+			key.letters = append(key.letters, base+int(letter))
+			if letter == 'v' {
+				letter = 'u'
+			}
+			key.spell = append(key.spell, base+int(letter))
+		}
+		key.tones = append(key.tones, tone)
+	}
+	return key
+}
+
 // 判断是否字母或汉字
 func (_ ReadingIndexCollator) IsLetter(r rune) bool {
 	r = unicode.ToLower(r)
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+)
+
+// 检查 less 中的每一对串都是前者小于后者
+func testStrcmpLess(t *testing.T, s IndexEntrySlice, less [][2]string) {
+	t.Helper()
+	for _, pair := range less {
+		if cmp := s.Strcmp(pair[0], pair[1]); cmp >= 0 {
+			t.Errorf("Strcmp(%q, %q) = %d, want < 0", pair[0], pair[1], cmp)
+		}
+		if cmp := s.Strcmp(pair[1], pair[0]); cmp <= 0 {
+			t.Errorf("Strcmp(%q, %q) = %d, want > 0", pair[1], pair[0], cmp)
+		}
+	}
+}
+
+func TestStrcmp_word(t *testing.T) {
+	// 逐字比较：先比较首字的读音
+	testStrcmpLess(t, IndexEntrySlice{colattor: ReadingIndexCollator{}}, [][2]string{
+		{"安心", "按"},
+		{"Zoo", "阿"},
+	})
+	// 整词比较：先比较整串的无声调拼音，再区分 u 与 ü，最后比较声调
+	testStrcmpLess(t, IndexEntrySlice{colattor: ReadingIndexCollator{word: true}}, [][2]string{
+		{"按", "安心"},
+		{"妈", "麻"},
+		{"麻", "马"},
+		{"路", "旅"},
+		{"旅", "绿"},
+		{"路", "女"},
+		{"Zoo", "阿"},
+	})
+}
+
+func TestStrcmp_interleave(t *testing.T) {
+	// 汉字的拼音拼写与拉丁字母一起比较，拼写相同时拉丁字母在前
+	testStrcmpLess(t, IndexEntrySlice{colattor: ReadingIndexCollator{interleave: true}}, [][2]string{
+		{"阿", "Apple"},
+		{"Bear", "本"},
+		{"ben", "本"},
+		{"本", "Bf"},
+		{"x-ray", "西"},
+	})
+}
+
+func TestOutput_interleave(t *testing.T) {
+	keys := []string{"Bf", "本", "Apple", "阿", "Bear"}
+	tests := []struct {
+		interleave int
+		want       string
+	}{
+		{0, "[A] Apple 阿 [B] Bear Bf 本"},
+		{1, "[A] 阿 Apple [B] Bear 本 Bf"},
+	}
+	for _, test := range tests {
+		style := newTestOutputStyle()
+		style.reading_interleave_flag = test.interleave
+		if got := writeTestIndex("pinyin", style, keys...); got != test.want {
+			t.Errorf("reading_interleave_flag %d: output = %q, want %q", test.interleave, got, test.want)
+		}
+	}
+}
+
+func TestOutput_surname(t *testing.T) {
+	keys := []string{"单雄信", "曾国藩", "解缙", "王安石", "仇英", "欧阳修"}
+	tests := []struct {
+		surname int
+		want    string
+	}{
+		{0, "[C] 曾国藩 仇英 [D] 单雄信 [J] 解缙 [O] 欧阳修 [W] 王安石"},
+		{1, "[O] 欧阳修 [Q] 仇英 [S] 单雄信 [W] 王安石 [X] 解缙 [Z] 曾国藩"},
+	}
+	for _, test := range tests {
+		style := newTestOutputStyle()
+		style.surname_flag = test.surname
+		if got := writeTestIndex("pinyin", style, keys...); got != test.want {
+			t.Errorf("surname_flag %d: output = %q, want %q", test.surname, got, test.want)
+		}
+	}
+}
@@ -3,57 +3,120 @@ package main
 import (
 	"log"
 	"sort"
-	"strconv"
 	"unicode"
 	"unicode/utf8"
 )
 
 // 对应不同的分类排序方式
 type IndexCollator interface {
-	// 初始化分组
+	// 初始化分组，分组可以带有预先确定的子分组，构成一棵树
 	InitGroups(style *OutputStyle) []IndexGroup
-	// 给索引项分组
-	Group(entry *IndexEntry) int
+	// 给索引项分组，返回分组在 InitGroups 所得的树中的路径，即各层子分组的下标
+	Group(entry *IndexEntry) []int
+	// 数字分组在 InitGroups 所得的树中的路径
+	NumberGroup() []int
 	// 单个字符比较
 	RuneCmp(a, b rune) int
 	// 判断是否字母或汉字
 	IsLetter(r rune) bool
 }
 
+// 可选的整串比较，在逐字符比较之前进行，结果为 0 时再逐字符比较
+type StringCollator interface {
+	StringCmp(a, b string) int
+}
+
+// 可选的动态子分组，返回索引项在 Group 所得分组内的子分组名，空串表示没有子分组
+// 排序后同一子分组的索引项通常是连续的，不连续时分别输出，以保持排序的次序
+type SubgroupCollator interface {
+	Subgroup(entry *IndexEntry, style *OutputStyle) string
+}
+
+// 可选的人名比较，a_name、b_name 表示串是否是人名，结果为 0 时再按一般的串比较
+type NameCollator interface {
+	NameCmp(a, b string, a_name, b_name bool) int
+}
+
 // 排序器
 type IndexSorter struct {
 	IndexCollator
+	cjk_number int // 汉字数字的处理方式，同 cjk_number_flag
 }
 
-func NewIndexSorter(method string) *IndexSorter {
+func NewIndexSorter(option *OutputOptions, style *OutputStyle) *IndexSorter {
+	if !isNumberFormat(style.heading_number_format) {
+		log.Fatalln("未知数字格式", style.heading_number_format)
+	}
+	if !isReadingFormat(style.reading_format) {
+		log.Fatalln("未知注音格式", style.reading_format)
+	}
+	if !isRangeAbbrev(style.range_abbrev) {
+		log.Fatalln("未知页码区间省略规则", style.range_abbrev)
+	}
+	return &IndexSorter{
+		IndexCollator: NewIndexCollator(option.sort, option, style),
+		cjk_number:    style.cjk_number_flag,
+	}
+}
+
+// 按排序方式名 method 生成 collator
+func NewIndexCollator(method string, option *OutputOptions, style *OutputStyle) IndexCollator {
+	nested := style.cjk_heading != ""
 	switch method {
 	case "bihua", "stroke":
-		return &IndexSorter{
-			IndexCollator: StrokeIndexCollator{},
-		}
+		return StrokeIndexCollator{nested: nested}
 	case "pinyin", "reading":
-		return &IndexSorter{
-			IndexCollator: ReadingIndexCollator{},
+		// 设置了 cjk_heading 时，汉字也单独按拼音首字母分组
+		separate := (style.reading_separate_flag != 0 || nested) && style.reading_interleave_flag == 0
+		return ReadingIndexCollator{
+			word:       option.word || style.reading_word_flag != 0,
+			interleave: style.reading_interleave_flag != 0,
+			cjk_number: style.cjk_number_flag != 0,
+			surname:    style.surname_flag != 0,
+			separate:   separate,
+			nested:     separate && nested,
 		}
 	case "bushou", "radical":
-		return &IndexSorter{
-			IndexCollator: RadicalIndexCollator{},
+		if style.radical_system != "kangxi" && style.radical_system != "gf" {
+			log.Fatalln("未知部首系统", style.radical_system)
 		}
+		return RadicalIndexCollator{gf: style.radical_system == "gf", nested: nested}
+	case "gbcode":
+		collator := NewGBCodeIndexCollator(newFallbackCollator("pinyin", option, style))
+		collator.nested = nested
+		return collator
+	case "big5code":
+		collator := NewBig5CodeIndexCollator(newFallbackCollator("stroke", option, style))
+		collator.nested = nested
+		return collator
 	default:
 		log.Fatalln("未知排序方式")
 	}
 	return nil
 }
 
+// 按编码排序时，不能编码的汉字使用的后备排序方式，默认为 method
+func newFallbackCollator(method string, option *OutputOptions, style *OutputStyle) IndexCollator {
+	if style.code_fallback != "" {
+		method = style.code_fallback
+	}
+	if method == "gbcode" || method == "big5code" {
+		log.Fatalln("code_fallback 不能是按编码的排序方式")
+	}
+	return NewIndexCollator(method, option, style)
+}
+
 func (sorter *IndexSorter) SortIndex(input *InputIndex, style *OutputStyle, option *OutputOptions) *OutputIndex {
 	out := new(OutputIndex)
 	// 分组
-	out.groups = sorter.InitGroups(style)
+	var refs []groupRef
+	out.groups, refs = layoutGroups(sorter.InitGroups(style), style)
 
 	// 先整体排序
 	sort.Sort(IndexEntrySlice{
-		entries:  *input,
-		colattor: sorter.IndexCollator,
+		entries:    *input,
+		colattor:   sorter.IndexCollator,
+		cjk_number: sorter.cjk_number,
 	})
 
 	// 再依次对页码排序，并分组添加
@@ -61,21 +124,70 @@ func (sorter *IndexSorter) SortIndex(input *InputIndex, style *OutputStyle, opti
 	for _, entry := range *input {
 		pageranges := pagesorter.Sort(entry)
 		pageranges = pagesorter.Merge(pageranges)
+		pageranges = pagesorter.PrimaryFirst(pageranges)
 		item := IndexItem{
 			level: len(entry.level) - 1,
 			text:  entry.level[len(entry.level)-1].text,
 			page:  pageranges,
 		}
-		group := sorter.Group(&entry)
-		out.groups[group].items = append(out.groups[group].items, item)
+		if style.readingFlag(item.level) != 0 {
+			item.reading = sorter.readingOf(&entry, style)
+		}
+		path := sorter.Group(&entry)
+		subgroup := ""
+		if sorter.cjk_number > 1 && IsCJKNumString(entry.level[0].key) {
+			// 汉字数字与阿拉伯数字分入同一组，数字组的位置由 collator 决定
+			path = sorter.NumberGroup()
+		} else if subcoll, ok := sorter.IndexCollator.(SubgroupCollator); ok && style.headings_flag != 0 {
+			subgroup = subcoll.Subgroup(&entry, style)
+		}
+		// 换为重排后的分组路径
+		if ref := refs[path[0]]; ref.merged {
+			path = ref.path
+		} else {
+			path = append(append([]int{}, ref.path...), path[1:]...)
+		}
+		group := &out.groups[path[0]]
+		for _, i := range path[1:] {
+			group = &group.subgroups[i]
+		}
+		group.add(subgroup, item)
 	}
+	// 合并稀疏的字母分组，以及按笔画数、拼音首字母划分的汉字分组
+	out.groups = mergeSparseGroups(out.groups, style, func(kind int) bool {
+		switch sorter.IndexCollator.(type) {
+		case StrokeIndexCollator, ReadingIndexCollator:
+			return kind == GROUP_LETTER || kind == GROUP_CJK
+		default:
+			return kind == GROUP_LETTER
+		}
+	})
 
 	return out
 }
 
+// 取得索引项最后一级的注音：用户指定的原样使用，否则由排序项（排序项不全是汉字时由输出文字）计算拼音
+// 按拼音排序以外的排序方式也使用 CJK.Readings 中的读音
+func (sorter *IndexSorter) readingOf(entry *IndexEntry, style *OutputStyle) string {
+	level := &entry.level[len(entry.level)-1]
+	if level.reading != "" {
+		return level.reading
+	}
+	collator, ok := sorter.IndexCollator.(ReadingIndexCollator)
+	if !ok {
+		collator = ReadingIndexCollator{surname: style.surname_flag != 0}
+	}
+	syllables := collator.Readings(level.key, level.name)
+	if syllables == nil {
+		syllables = collator.Readings(level.text, level.name)
+	}
+	return FormatReading(syllables, style.reading_format, style.reading_capital_flag != 0)
+}
+
 type IndexEntrySlice struct {
-	entries  []IndexEntry
-	colattor IndexCollator
+	entries    []IndexEntry
+	colattor   IndexCollator
+	cjk_number int
 }
 
 func (s IndexEntrySlice) Len() int {
@@ -88,28 +200,57 @@ func (s IndexEntrySlice) Swap(i, j int) {
 
 // 比较两个串的大小
 func (s IndexEntrySlice) Strcmp(a, b string) int {
-	atype, btype := getStringType(s.colattor, a), getStringType(s.colattor, b)
+	return s.strcmp(a, b, false, false)
+}
+
+// 比较两个串的大小，a_name、b_name 表示串是否是人名
+func (s IndexEntrySlice) strcmp(a, b string, a_name, b_name bool) int {
+	atype, btype := s.stringType(a), s.stringType(b)
 	if atype < btype {
 		return -1
 	} else if atype > btype {
 		return 1
 	}
-	// 特例：尝试按纯数字比较
+	// 特例：尝试按实数大小比较
 	if cmp := DecimalStrcmp(a, b); cmp != 0 {
 		return cmp
 	}
-	// 忽略大小写，按字典序比较
+	// 按人名比较，如按姓氏读音比较
+	if namecoll, ok := s.colattor.(NameCollator); ok {
+		if cmp := namecoll.NameCmp(a, b, a_name, b_name); cmp != 0 {
+			return cmp
+		}
+	}
+	// 按整串比较，如拼音的整词比较
+	if strcoll, ok := s.colattor.(StringCollator); ok {
+		if cmp := strcoll.StringCmp(a, b); cmp != 0 {
+			return cmp
+		}
+	}
+	// 忽略大小写，按字典序比较，其中连续的数字按数值比较
 	a_rune, b_rune := []rune(a), []rune(b)
-	for i := range a_rune {
-		if i >= len(b_rune) {
-			return 1
+	i, j := 0, 0
+	for i < len(a_rune) && j < len(b_rune) {
+		a_num, a_len := scanNumeral(a_rune[i:], s.cjk_number != 0)
+		b_num, b_len := scanNumeral(b_rune[j:], s.cjk_number != 0)
+		if a_len > 0 && b_len > 0 {
+			if cmp := a_num.Cmp(b_num); cmp != 0 {
+				return cmp
+			}
+			i += a_len
+			j += b_len
+			continue
 		}
-		cmp := s.colattor.RuneCmp(a_rune[i], b_rune[i])
+		cmp := s.colattor.RuneCmp(a_rune[i], b_rune[j])
 		if cmp != 0 {
 			return cmp
 		}
+		i++
+		j++
 	}
-	if len(a_rune) < len(b_rune) {
+	if i < len(a_rune) {
+		return 1
+	} else if j < len(b_rune) {
 		return -1
 	}
 	// 不忽略大小写重新比较串，此时不必使用 colattor 特有的比较
@@ -135,19 +276,19 @@ const (
 )
 
 // 取得串类型
-func getStringType(collator IndexCollator, s string) stringType {
+func (slice IndexEntrySlice) stringType(s string) stringType {
 	if len(s) == 0 {
 		return EMPTY_STR
 	}
 	r, _ := utf8.DecodeRuneInString(s)
 	switch {
+	case IsNumString(s):
+		return NUM_STR
+	case slice.cjk_number > 1 && IsCJKNumString(s):
+		return NUM_STR
 	case IsNumRune(r):
-		if IsNumString(s) {
-			return NUM_STR
-		} else {
-			return NUM_SYMBOL_STR
-		}
-	case collator.IsLetter(r):
+		return NUM_SYMBOL_STR
+	case slice.colattor.IsLetter(r):
 		return LETTER_STR
 	default:
 		return SYMBOL_STR
@@ -160,13 +301,13 @@ func (s IndexEntrySlice) Less(i, j int) bool {
 		if i >= len(b.level) {
 			return false
 		}
-		keycmp := s.Strcmp(a.level[i].key, b.level[i].key)
+		keycmp := s.strcmp(a.level[i].key, b.level[i].key, a.level[i].name, b.level[i].name)
 		if keycmp < 0 {
 			return true
 		} else if keycmp > 0 {
 			return false
 		}
-		textcmp := s.Strcmp(a.level[i].text, b.level[i].text)
+		textcmp := s.strcmp(a.level[i].text, b.level[i].text, a.level[i].name, b.level[i].name)
 		if textcmp < 0 {
 			return true
 		} else if textcmp > 0 {
@@ -184,46 +325,73 @@ type PageSorter struct {
 	precedence    map[NumFormat]int
 	strict        bool
 	disable_range bool
+	split         int            // 页码区间跨过不同数字格式时的处理方式，同 range_split_flag
+	encap_order   map[string]int // encap_precedence 中各命令的次序，越小越优先
+	primary_order map[string]int // primary_encap 中各命令的次序，这些页码排在最前
+	pagelist      []*Page        // 文档中各页的页码
+	pageindex     map[string]int // 页码在 pagelist 中的位置
 }
 
 func NewPageSorter(style *OutputStyle, option *OutputOptions) *PageSorter {
 	var sorter PageSorter
-	sorter.precedence = make(map[NumFormat]int)
-	for i, r := range style.page_precedence {
-		switch r {
-		case 'r':
-			sorter.precedence[NUM_ROMAN_LOWER] = i
-		case 'n':
-			sorter.precedence[NUM_ARABIC] = i
-		case 'a':
-			sorter.precedence[NUM_ALPH_LOWER] = i
-		case 'R':
-			sorter.precedence[NUM_ROMAN_UPPER] = i
-		case 'A':
-			sorter.precedence[NUM_ALPH_UPPER] = i
-		default:
-			log.Println("page_precedence 语法错误，采用默认值")
-			sorter.precedence = map[NumFormat]int{
-				NUM_ROMAN_LOWER: 0,
-				NUM_ARABIC:      1,
-				NUM_ALPH_LOWER:  2,
-				NUM_ROMAN_UPPER: 3,
-				NUM_ALPH_UPPER:  4,
-			}
-		}
-	}
+	sorter.precedence = parsePagePrecedence(style.page_precedence)
 	sorter.strict = option.strict
 	sorter.disable_range = option.disable_range
+	sorter.split = style.range_split_flag
+	// 重复列出的命令只取第一次出现的次序，次序连续编号
+	sorter.primary_order = make(map[string]int)
+	for _, encap := range style.primary_encap {
+		if _, ok := sorter.primary_order[encap]; !ok {
+			sorter.primary_order[encap] = len(sorter.primary_order)
+		}
+	}
+	sorter.encap_order = make(map[string]int)
+	for _, encap := range style.encap_precedence {
+		if _, ok := sorter.encap_order[encap]; !ok {
+			sorter.encap_order[encap] = len(sorter.encap_order)
+		}
+	}
+	sorter.pagelist = option.pagelist
+	sorter.pageindex = make(map[string]int)
+	for i, p := range sorter.pagelist {
+		if _, ok := sorter.pageindex[p.String()]; !ok {
+			sorter.pageindex[p.String()] = i
+		}
+	}
 	return &sorter
 }
 
+// 解析 page_precedence，得到各数字格式的次序
+// 未列出的格式按 defaultPagePrecedence 中的次序排在最后；有语法错误时采用默认值
+func parsePagePrecedence(precedence string) map[NumFormat]int {
+	order := make(map[NumFormat]int)
+	for _, r := range precedence {
+		format, ok := numFormatLetters[r]
+		if !ok {
+			log.Println("page_precedence 语法错误，采用默认值")
+			return parsePagePrecedence(defaultPagePrecedence)
+		}
+		if _, listed := order[format]; !listed {
+			order[format] = len(order)
+		}
+	}
+	for _, r := range defaultPagePrecedence {
+		if _, listed := order[numFormatLetters[r]]; !listed {
+			order[numFormatLetters[r]] = len(order)
+		}
+	}
+	return order
+}
+
 // 处理输入的页码，生成页码区间组
 func (sorter *PageSorter) Sort(entry IndexEntry) []PageRange {
 	pages := entry.pagelist
 	//debug.Println(entry.input, pages)
 	var out []PageRange
 	// 合并前排序。传统 Makeindex 按原始输入的次序，在处理多个文件时可能不大好
-	if sorter.strict {
+	// 设置 encap_precedence 时也按命令分开排序，各命令的页码分别合并为区间，
+	// 不同命令的区间可以重叠，重叠的部分由 applyEncapPrecedence 处理
+	if sorter.strict || len(sorter.encap_order) > 0 {
 		sort.Sort(PageSliceStrict{
 			PageSlice{pages: pages, sorter: sorter}})
 	} else {
@@ -233,7 +401,9 @@ func (sorter *PageSorter) Sort(entry IndexEntry) []PageRange {
 	//debug.Println(pages)
 	// 使用一个栈来合并页码区间
 	// 这里的合并只将 1( 2 3 3) 合并为 1--3，不处理相邻区间，后者需要再做 Merge 操作
+	// last 是当前区间中与区间头数字格式相同的最后一页
 	var stack []*Page
+	var last *Page
 	for i := 0; i < len(pages); i++ {
 		p := pages[i]
 		//debug.Printf("处理页码 %s{%s} %s\n", p.encap, p.NumString(), p.rangetype)
@@ -245,6 +415,7 @@ func (sorter *PageSorter) Sort(entry IndexEntry) []PageRange {
 			case PAGE_OPEN:
 				// 压栈
 				stack = append(stack, p)
+				last = p
 			case PAGE_CLOSE:
 				log.Printf("条目 %s 的页码区间有误，区间末尾 %s{%s} 没有匹配的区间头。\n", entry.input, p.encap, p)
 				// 输出从空白到当前页的伪区间
@@ -272,8 +443,21 @@ func (sorter *PageSorter) Sort(entry IndexEntry) []PageRange {
 					}
 				}
 			} else if !p.Compatible(top) {
-				// 标准 Makeindex 会尝试把区间断开，这里只给出警告
-				log.Printf("条目 %s 的页码区间 %s{%s -- %s} 跨过不同的数字格式\n", entry.input, top.encap, top, p)
+				if sorter.split != 1 {
+					log.Printf("条目 %s 的页码区间 %s{%s -- %s} 跨过不同的数字格式\n", entry.input, top.encap, top, p)
+				}
+				if sorter.split != 0 {
+					// 与 Makeindex 一样把区间断开：前一段到前一种格式的最后一页，后一段从后一种格式的第一页开始
+					end, next := sorter.splitRange(front, last, p)
+					out = append(out, PageRange{begin: front, end: end})
+					for j := range stack {
+						stack[j] = next
+					}
+					front, top = next, next
+				}
+				last = p
+			} else {
+				last = p
 			}
 			switch p.rangetype {
 			case PAGE_NORMAL:
@@ -296,9 +480,71 @@ func (sorter *PageSorter) Sort(entry IndexEntry) []PageRange {
 		out = append(out, PageRange{begin: stack[0], end: stack[0].Empty()})
 	}
 	//	debug.Println(out)
+	return sorter.applyEncapPrecedence(out)
+}
+
+// 按 encap_precedence 去掉被更优先的命令覆盖的页码：
+// 同一页有多个命令时只保留最优先的，区间与更优先的区间或单页重叠时去掉重叠的部分，
+// 余下的部分仍作为原命令的区间输出；处理后的区间按区间头排序
+// 两个命令都在 encap_precedence 中列出时才比较，没有列出的命令（如 see）总是分别输出
+func (sorter *PageSorter) applyEncapPrecedence(ranges []PageRange) []PageRange {
+	if len(sorter.encap_order) == 0 {
+		return ranges
+	}
+	// a 的命令是否比 b 的优先
+	prior := func(a, b *Page) bool {
+		a_order, a_ok := sorter.encap_order[a.encap]
+		b_order, b_ok := sorter.encap_order[b.encap]
+		return a_ok && b_ok && a_order < b_order
+	}
+	var out []PageRange
+	for i, r := range ranges {
+		pieces := []PageRange{r}
+		for j, other := range ranges {
+			if j == i || !prior(other.begin, r.begin) {
+				continue
+			}
+			var rest []PageRange
+			for _, piece := range pieces {
+				rest = append(rest, piece.subtract(other)...)
+			}
+			pieces = rest
+		}
+		out = append(out, pieces...)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].begin.Cmp(out[j].begin, sorter.precedence) < 0
+	})
 	return out
 }
 
+// 在数字格式改变处断开从 front 开始的页码区间，last 是区间中与 front 格式相同的最后一页，
+// p 是格式不同的第一页。返回前一段的区间尾与后一段的区间头
+// 有页码列表时，前一段延续到列表中同一格式的最后一页，后一段从列表中下一种格式的第一页开始
+func (sorter *PageSorter) splitRange(front, last, p *Page) (end, next *Page) {
+	end, next = last, p
+	if i, ok := sorter.pageindex[front.String()]; ok && sorter.pagelist[i].Compatible(front) {
+		for i+1 < len(sorter.pagelist) && sorter.pagelist[i+1].Compatible(front) {
+			i++
+		}
+		if sorter.pagelist[i].Cmp(last, sorter.precedence) > 0 {
+			end = sorter.pagelist[i]
+		}
+		if i+1 < len(sorter.pagelist) && sorter.pagelist[i+1].Compatible(p) &&
+			sorter.pagelist[i+1].Cmp(p, sorter.precedence) < 0 {
+			next = sorter.pagelist[i+1]
+		}
+	}
+	// 区间尾、区间头使用原区间的命令
+	if end != last {
+		end = &Page{numbers: end.numbers, compositor: end.compositor, class: end.class,
+			encap: front.encap, rangetype: PAGE_CLOSE}
+	}
+	next = &Page{numbers: next.numbers, compositor: next.compositor, class: next.class,
+		encap: front.encap, rangetype: PAGE_OPEN}
+	return end, next
+}
+
 // 合并相邻的页码区间
 // 输入是 1 2--3 4--6 7，输出 1--7
 func (sorter *PageSorter) Merge(pages []PageRange) []PageRange {
@@ -321,7 +567,7 @@ func (sorter *PageSorter) Merge(pages []PageRange) []PageRange {
 			}
 		} else if prev.begin.encap == r.begin.encap &&
 			r.begin.Compatible(prev.begin) &&
-			r.begin.Diff(prev.end) <= 1 {
+			r.begin.Diff(prev.end) <= r.begin.mergeGap() {
 			// 合并区间，只用后一区间尾替换前一区间尾
 			out[len(out)-1].end = r.end
 		} else {
@@ -342,6 +588,28 @@ func (sorter *PageSorter) Merge(pages []PageRange) []PageRange {
 	return out
 }
 
+// 把 primary_encap 中的命令修饰的页码区间移到最前，按 primary_encap 中的次序排列
+// 在合并区间之后进行，不影响区间合并；同一命令的区间与其余区间仍保持原来的次序
+func (sorter *PageSorter) PrimaryFirst(ranges []PageRange) []PageRange {
+	if len(sorter.primary_order) == 0 {
+		return ranges
+	}
+	// 按次序分桶，最后一个桶是其余的区间
+	buckets := make([][]PageRange, len(sorter.primary_order)+1)
+	for _, r := range ranges {
+		rank, ok := sorter.primary_order[r.begin.encap]
+		if !ok {
+			rank = len(buckets) - 1
+		}
+		buckets[rank] = append(buckets[rank], r)
+	}
+	var out []PageRange
+	for _, bucket := range buckets {
+		out = append(out, bucket...)
+	}
+	return out
+}
+
 type PageSlice struct {
 	pages  []*Page
 	sorter *PageSorter
@@ -413,9 +681,30 @@ func IsNumRune(r rune) bool {
 	return unicode.IsNumber(r) && r != '〇'
 }
 
-// 测试是否为数字串
+// 测试是否是十进制数字，包括全角数字等其他文字的数字
+func IsDigitRune(r rune) bool {
+	return unicode.IsDigit(r)
+}
+
+// 取得十进制数字的值
+// Unicode 中的十进制数字总是从 0 到 9 连续编码，因此可以从连续数字段的开头推算
+func digitValue(r rune) int {
+	if '0' <= r && r <= '9' {
+		return int(r - '0')
+	}
+	start := r
+	for unicode.IsDigit(start - 1) {
+		start--
+	}
+	return int(r-start) % 10
+}
+
+// 测试是否为数字串，包括可带正负号、千位分隔符和小数部分的实数
 // 此过程被其他 collator 的 RuneCmp 调用
 func IsNumString(s string) bool {
+	if _, ok := ParseDecimal(s); ok {
+		return true
+	}
 	for _, r := range s {
 		if !IsNumRune(r) {
 			return false
@@ -424,22 +713,190 @@ func IsNumString(s string) bool {
 	return true
 }
 
-// 按数字大小比较自然数串，如果不是自然数串视为相等
+// 按实数大小比较数字串，如果不是实数串视为相等
 func DecimalStrcmp(a, b string) int {
-	aint, err := strconv.ParseUint(a, 10, 64)
-	if err != nil {
+	anum, ok := ParseDecimal(a)
+	if !ok {
 		return 0
 	}
-	bint, err := strconv.ParseUint(b, 10, 64)
-	if err != nil {
+	bnum, ok := ParseDecimal(b)
+	if !ok {
 		return 0
 	}
-	switch {
-	case aint < bint:
-		return -1
-	case aint > bint:
+	return anum.Cmp(bnum)
+}
+
+// 从 token 开头读入阿拉伯数字，cjk 为真时也读入汉字数字，返回读入的字符数
+// 不是数字时读入字符数为 0。只读入连续的数字，不把“.”“,”当作小数点与千位分隔符，
+// 因此“第1.10节”中的 1 与 10 分别按自然数比较
+func scanNumeral(token []rune, cjk bool) (Decimal, int) {
+	if len(token) == 0 {
+		return Decimal{}, 0
+	}
+	if IsDigitRune(token[0]) {
+		return scanDigits(token)
+	}
+	if cjk {
+		if num, n := scanCJKNumber(token); n > 0 {
+			return num, n
+		}
+	}
+	return Decimal{}, 0
+}
+
+// 十进制实数，用于数字的自然排序，不限位数
+type Decimal struct {
+	negative bool
+	integer  []int // 整数部分各位数字，无前导零
+	fraction []int // 小数部分各位数字，无末尾零
+}
+
+// 将整个串解析为实数，可带正负号
+func ParseDecimal(s string) (Decimal, bool) {
+	token := []rune(s)
+	negative := false
+	if len(token) > 0 {
+		switch token[0] {
+		case '-', '−', '－':
+			negative = true
+			token = token[1:]
+		case '+', '＋':
+			token = token[1:]
+		}
+	}
+	if len(token) == 0 || !IsDigitRune(token[0]) {
+		return Decimal{}, false
+	}
+	num, n := scanDecimal(token)
+	if n != len(token) {
+		return Decimal{}, false
+	}
+	num.negative = negative && !num.IsZero()
+	return num, true
+}
+
+// 从 token 开头读入无符号的十进制数，返回读入的字符数
+// 数字间可以有千位分隔符“,”，数字后可以有以“.”开始的小数部分
+func scanDecimal(token []rune) (Decimal, int) {
+	var num Decimal
+	// 之后的 n 个字符都是数字
+	digits := func(start int) int {
+		n := 0
+		for start+n < len(token) && IsDigitRune(token[start+n]) {
+			n++
+		}
+		return n
+	}
+	i := 0
+	for n := digits(i); n > 0; {
+		for _, r := range token[i : i+n] {
+			num.integer = append(num.integer, digitValue(r))
+		}
+		i += n
+		// 千位分隔符后必须恰好有三位数字
+		if i < len(token) && token[i] == ',' && digits(i+1) == 3 {
+			i++
+			n = 3
+		} else {
+			n = 0
+		}
+	}
+	if i+1 < len(token) && token[i] == '.' {
+		if n := digits(i + 1); n > 0 {
+			for _, r := range token[i+1 : i+1+n] {
+				num.fraction = append(num.fraction, digitValue(r))
+			}
+			i += 1 + n
+		}
+	}
+	for len(num.integer) > 0 && num.integer[0] == 0 {
+		num.integer = num.integer[1:]
+	}
+	for len(num.fraction) > 0 && num.fraction[len(num.fraction)-1] == 0 {
+		num.fraction = num.fraction[:len(num.fraction)-1]
+	}
+	return num, i
+}
+
+// 从 token 开头读入连续的十进制数字，作为自然数，返回读入的字符数
+func scanDigits(token []rune) (Decimal, int) {
+	var num Decimal
+	i := 0
+	for i < len(token) && IsDigitRune(token[i]) {
+		num.integer = append(num.integer, digitValue(token[i]))
+		i++
+	}
+	for len(num.integer) > 0 && num.integer[0] == 0 {
+		num.integer = num.integer[1:]
+	}
+	return num, i
+}
+
+// 转换为 int，不是整数或超出范围时返回 false
+func (d Decimal) Int() (int, bool) {
+	if d.negative || len(d.fraction) > 0 {
+		return 0, false
+	}
+	num := 0
+	for _, digit := range d.integer {
+		if num > (MaxInt-digit)/10 {
+			return 0, false
+		}
+		num = num*10 + digit
+	}
+	return num, true
+}
+
+func (d Decimal) IsZero() bool {
+	return len(d.integer) == 0 && len(d.fraction) == 0
+}
+
+// 比较两个实数的大小，返回负、零、正值
+func (d Decimal) Cmp(other Decimal) int {
+	if d.negative != other.negative {
+		if d.negative {
+			return -1
+		}
 		return 1
-	default:
-		return 0
 	}
+	cmp := d.cmpAbs(other)
+	if d.negative {
+		return -cmp
+	}
+	return cmp
+}
+
+func (d Decimal) cmpAbs(other Decimal) int {
+	if len(d.integer) != len(other.integer) {
+		return len(d.integer) - len(other.integer)
+	}
+	if cmp := compareInts(d.integer, other.integer); cmp != 0 {
+		return cmp
+	}
+	return compareInts(d.fraction, other.fraction)
+}
+
+// 生成可以按字典序比较的排序键，与数字 '0' 的位置相当
+// 形如 '0', 整数位数, 各位数字..., -1
+func (d Decimal) sortKey() []int {
+	key := []int{'0', len(d.integer)}
+	key = append(key, d.integer...)
+	key = append(key, d.fraction...)
+	return append(key, -1)
+}
+
+// 按字典序比较两个整数串
+func compareInts(a, b []int) int {
+	for i := range a {
+		if i >= len(b) {
+			return 1
+		}
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	if len(a) < len(b) {
+		return -1
+	}
+	return 0
 }
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leo-liu/zhmakeindex/CJK"
+)
+
+// 测试用的输出格式：分组名写作 [名]，子分组名写作 <名>，各项以空格分隔
+func newTestOutputStyle() *OutputStyle {
+	style := NewOutputStyle()
+	style.headings_flag = 1
+	style.heading_prefix, style.heading_suffix = " [", "]"
+	style.subheading_prefix, style.subheading_suffix = " <", ">"
+	style.group_skip, style.subgroup_skip = "", ""
+	style.item_0 = " "
+	return style
+}
+
+// 按排序方式 method 排序、分组，输出以 keys 为索引项的索引，各项没有页码
+func writeTestIndex(method string, style *OutputStyle, keys ...string) string {
+	input := make(InputIndex, len(keys))
+	for i, key := range keys {
+		input[i] = IndexEntry{input: key, level: []IndexEntryLevel{{key: key, text: key}}}
+	}
+	option := &OutputOptions{sort: method}
+	out := NewIndexSorter(option, style).SortIndex(&input, style, option)
+	out.style, out.option = style, option
+	var buf bytes.Buffer
+	out.writeNavbar(&buf)
+	out.writeGroups(&buf, out.groups, "", 0, true)
+	return strings.TrimSpace(buf.String())
+}
+
+// 读入测试用的页码，复合页码以 "-" 分隔
+func testPage(t *testing.T, s, encap string, rt RangeType) *Page {
+	p := &Page{compositor: "-", encap: encap, rangetype: rt}
+	if err := p.scanLocator([]rune(s), NewInputStyle()); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestStrcmp_natural(t *testing.T) {
+	s := IndexEntrySlice{colattor: ReadingIndexCollator{}}
+	less := [][2]string{
+		{"第2章", "第10章"},
+		{"Item 2", "Item 10"},
+		{"Item 02", "Item 3"},
+		{"２", "１０"},
+		{"-10", "-2"},
+		{"-2", "1.5"},
+		{"1.25", "1.5"},
+		{"999", "1,000"},
+		{"1,000.5", "1001"},
+		{"9", "１０"},
+		{"第1.9节", "第1.10节"},
+		{"1.1", "1.10"},
+		{"v1,5", "v1,20"},
+	}
+	for _, pair := range less {
+		if cmp := s.Strcmp(pair[0], pair[1]); cmp >= 0 {
+			t.Errorf("Strcmp(%q, %q) = %d, want < 0", pair[0], pair[1], cmp)
+		}
+		if cmp := s.Strcmp(pair[1], pair[0]); cmp <= 0 {
+			t.Errorf("Strcmp(%q, %q) = %d, want > 0", pair[1], pair[0], cmp)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, str := range []string{"0", "-3", "+3", "1,234,567", "3.14", "１２", "−1"} {
+		if _, ok := ParseDecimal(str); !ok {
+			t.Errorf("ParseDecimal(%q) failed", str)
+		}
+	}
+	for _, str := range []string{"", "-", "1,23", "1.", "a1", "1a", "12,3456"} {
+		if _, ok := ParseDecimal(str); ok {
+			t.Errorf("ParseDecimal(%q) should fail", str)
+		}
+	}
+	a, _ := ParseDecimal("-0")
+	b, _ := ParseDecimal("0.00")
+	if a.Cmp(b) != 0 {
+		t.Error("-0 != 0.00")
+	}
+}
+
+func TestLayoutGroups(t *testing.T) {
+	style := NewOutputStyle()
+	style.group_order = "cjk=汉字 letters symbols+numbers=其他"
+	groups := make([]IndexGroup, 2+26+3)
+	layout, refs := layoutGroups(groups, style)
+	if len(layout) != 1+26+1 {
+		t.Fatalf("len(layout) = %d, want %d", len(layout), 1+26+1)
+	}
+	if layout[0].name != "汉字" || len(layout[0].subgroups) != 3 || layout[27].name != "其他" {
+		t.Errorf("wrong layout: %q, %d subgroups, %q", layout[0].name, len(layout[0].subgroups), layout[27].name)
+	}
+	want := map[int]groupRef{
+		0:      {path: []int{27}, merged: true},
+		1:      {path: []int{27}, merged: true},
+		2:      {path: []int{1}},
+		2 + 26: {path: []int{0, 0}},
+	}
+	for i, ref := range want {
+		if !reflect.DeepEqual(refs[i], ref) {
+			t.Errorf("refs[%d] = %v, want %v", i, refs[i], ref)
+		}
+	}
+}
+
+func TestPageSorterSplit(t *testing.T) {
+	entry := func() IndexEntry {
+		return IndexEntry{pagelist: []*Page{
+			testPage(t, "xii", "", PAGE_OPEN), testPage(t, "xiii", "", PAGE_NORMAL), testPage(t, "3", "", PAGE_CLOSE),
+		}}
+	}
+	ranges := func(sorter *PageSorter) []string {
+		var s []string
+		for _, r := range sorter.Merge(sorter.Sort(entry())) {
+			s = append(s, r.begin.String()+"--"+r.end.String())
+		}
+		return s
+	}
+	style := NewOutputStyle()
+	style.range_split_flag = 1
+	option := &OutputOptions{}
+	if got, want := ranges(NewPageSorter(style, option)), []string{"xii--xiii", "3--3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("split = %q, want %q", got, want)
+	}
+	for _, s := range []string{"xi", "xii", "xiii", "xiv", "1", "2", "3"} {
+		option.pagelist = append(option.pagelist, testPage(t, s, "", PAGE_NORMAL))
+	}
+	if got, want := ranges(NewPageSorter(style, option)), []string{"xii--xiv", "1--3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("split with page list = %q, want %q", got, want)
+	}
+	style.range_split_flag = 0
+	if got, want := ranges(NewPageSorter(style, option)), []string{"xii--3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("no split = %q, want %q", got, want)
+	}
+}
+
+func TestEncapPrecedence(t *testing.T) {
+	entry := IndexEntry{pagelist: []*Page{
+		testPage(t, "5", "", PAGE_NORMAL), testPage(t, "5", "textbf", PAGE_NORMAL), testPage(t, "5", "textit", PAGE_NORMAL),
+		testPage(t, "11", "textbf", PAGE_OPEN), testPage(t, "12", "", PAGE_NORMAL), testPage(t, "14", "textbf", PAGE_CLOSE),
+		testPage(t, "12", "emph", PAGE_NORMAL),
+		testPage(t, "20", "", PAGE_OPEN), testPage(t, "26", "", PAGE_CLOSE),
+		testPage(t, "24", "textbf", PAGE_OPEN), testPage(t, "30", "textbf", PAGE_CLOSE),
+		testPage(t, "40", "", PAGE_OPEN), testPage(t, "50", "", PAGE_CLOSE),
+		testPage(t, "44", "textit", PAGE_OPEN), testPage(t, "45", "textit", PAGE_CLOSE),
+	}}
+	style := NewOutputStyle()
+	style.encap_precedence = []string{"textbf", "textit", ""}
+	sorter := NewPageSorter(style, &OutputOptions{})
+	var got []string
+	for _, r := range sorter.Merge(sorter.Sort(entry)) {
+		got = append(got, r.begin.encap+"{"+r.begin.String()+"--"+r.end.String()+"}")
+	}
+	want := []string{"textbf{5--5}", "textbf{11--14}", "emph{12--12}", "{20--23}", "textbf{24--30}",
+		"{40--43}", "textit{44--45}", "{46--50}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("encap precedence = %q, want %q", got, want)
+	}
+}
+
+func TestPrimaryFirst(t *testing.T) {
+	ranges := []PageRange{
+		{testPage(t, "3", "", PAGE_NORMAL), testPage(t, "5", "", PAGE_NORMAL)},
+		{testPage(t, "7", "textbf", PAGE_NORMAL), testPage(t, "7", "textbf", PAGE_NORMAL)},
+		{testPage(t, "9", "", PAGE_NORMAL), testPage(t, "9", "", PAGE_NORMAL)},
+		{testPage(t, "20", "main", PAGE_NORMAL), testPage(t, "22", "main", PAGE_NORMAL)},
+	}
+	style := NewOutputStyle()
+	style.primary_encap = []string{"main", "textbf"}
+	var got []string
+	for _, r := range NewPageSorter(style, &OutputOptions{}).PrimaryFirst(ranges) {
+		got = append(got, r.begin.encap+"{"+r.begin.String()+"}")
+	}
+	want := []string{"main{20}", "textbf{7}", "{3}", "{9}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PrimaryFirst = %q, want %q", got, want)
+	}
+	// 重复列出的命令
+	style.primary_encap = []string{"main", "main", "main", "textbf"}
+	got = nil
+	for _, r := range NewPageSorter(style, &OutputOptions{}).PrimaryFirst(ranges) {
+		got = append(got, r.begin.encap+"{"+r.begin.String()+"}")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PrimaryFirst with repeated encap = %q, want %q", got, want)
+	}
+}
+
+func TestRadicalStroke201(t *testing.T) {
+	c := RadicalIndexCollator{gf: true}
+	tests := []struct {
+		char    rune
+		radical rune
+		stroke  int
+	}{
+		{'五', '一', 3},
+		{'甩', '冂', 3},
+		{'声', '土', 4},
+		{'江', '水', 3},
+		{'业', '业', 0}, // 康熙字典归“一”部
+		{'卤', '卤', 0},
+		{'亻', '人', 0},
+	}
+	for _, test := range tests {
+		rs := c.radicalStroke(test.char)
+		if radical := CJK.Radicals201[rs.Radical()].Origin; radical != test.radical || rs.Stroke() != test.stroke {
+			t.Errorf("radicalStroke(%c) = %c+%d, want %c+%d", test.char, radical, rs.Stroke(), test.radical, test.stroke)
+		}
+	}
+	// 每个主部首字都归入本部
+	for i := 1; i <= CJK.MAX_RADICAL201; i++ {
+		r := CJK.Radicals201[i].Origin
+		if rs := c.radicalStroke(r); rs.Radical() != i || rs.Stroke() != 0 {
+			t.Errorf("radicalStroke(%c) = %d+%d, want %d+0", r, rs.Radical(), rs.Stroke(), i)
+		}
+	}
+}
+
+func TestIndexGroupAdd(t *testing.T) {
+	var group IndexGroup
+	for _, add := range [][2]string{{"", "Apple"}, {"ā", "阿"}, {"", "Abc"}, {"ā", "啊"}, {"ài", "爱"}, {"ā", "阿姨"}} {
+		group.add(add[0], IndexItem{text: add[1]})
+	}
+	var got []string
+	for _, item := range group.items {
+		got = append(got, item.text)
+	}
+	for _, sub := range group.subgroups {
+		got = append(got, "["+sub.name+"]")
+		for _, item := range sub.items {
+			got = append(got, item.text)
+		}
+	}
+	want := []string{"Apple", "Abc", "[ā]", "阿", "啊", "[ài]", "爱", "[ā]", "阿姨"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("group = %q, want %q", got, want)
+	}
+	// 没有子分组名的项在第一个子分组之前输出，不接在前一子分组之后
+	style := newTestOutputStyle()
+	style.reading_subheading_flag = 1
+	style.reading_interleave_flag = 1
+	if got, want := writeTestIndex("pinyin", style, "洗", "x-ray", "西", "xylophone"), "[X] x-ray xylophone <xī> 西 <xǐ> 洗"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestCJKHeadingPath(t *testing.T) {
+	style := NewOutputStyle()
+	style.cjk_heading = "汉字"
+	entry := &IndexEntry{level: []IndexEntryLevel{{key: "木", text: "木"}}}
+	for _, method := range []string{"stroke", "pinyin"} {
+		collator := NewIndexCollator(method, &OutputOptions{}, style)
+		groups := collator.InitGroups(style)
+		if len(groups) != 2+26+1 || groups[2+26].name != "汉字" {
+			t.Fatalf("%s: %d groups, last %q", method, len(groups), groups[len(groups)-1].name)
+		}
+		path := collator.Group(entry)
+		if len(path) != 2 || path[0] != 2+26 || path[1] >= len(groups[2+26].subgroups) {
+			t.Errorf("%s: Group(木) = %v", method, path)
+		}
+	}
+	layout, refs := layoutGroups(NewIndexCollator("stroke", &OutputOptions{}, style).InitGroups(style), style)
+	if ref := refs[2+26]; !reflect.DeepEqual(ref.path, []int{2 + 26}) || layout[2+26].subgroups[3].kind != GROUP_CJK {
+		t.Errorf("refs[%d] = %v", 2+26, ref)
+	}
+}
+
+func TestMergeSparseGroups(t *testing.T) {
+	style := NewOutputStyle()
+	style.group_min_size = 3
+	style.group_merge_joiner = "–"
+	stroke := func(n string, subgroups ...IndexGroup) IndexGroup {
+		return IndexGroup{name: n + " 画", kind: GROUP_CJK, subgroups: subgroups, name_suffix: " 画"}
+	}
+	sub := func(name string, texts ...string) IndexGroup {
+		group := IndexGroup{name: name, kind: GROUP_CJK, dynamic: true}
+		for _, text := range texts {
+			group.items = append(group.items, IndexItem{text: text})
+		}
+		return group
+	}
+	groups := []IndexGroup{
+		stroke("1", sub("横", "一")),
+		stroke("2", sub("横", "二"), sub("撇", "人")),
+		stroke("3", sub("横", "三")),
+		stroke("4"),
+		stroke("5", sub("横", "正")),
+		stroke("6", sub("撇", "年")),
+	}
+	// 合并到 3 项即止，空分组把稀疏分组隔开
+	merged := mergeSparseGroups(groups, style, func(kind int) bool { return true })
+	var names []string
+	for _, group := range merged {
+		names = append(names, group.name)
+	}
+	wantNames := []string{"1–2 画", "3 画", "4 画", "5–6 画"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("merged names = %q, want %q", names, wantNames)
+	}
+	var got []string
+	for _, sub := range merged[0].subgroups {
+		got = append(got, "["+sub.name+"]")
+		for _, item := range sub.items {
+			got = append(got, item.text)
+		}
+	}
+	want := []string{"[横]", "一", "二", "[撇]", "人"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged = %q, want %q", got, want)
+	}
+	style.group_merge_list_flag = 1
+	style.group_merge_joiner = ", "
+	merged = mergeSparseGroups(groups[4:], style, func(kind int) bool { return true })
+	if len(merged) != 1 || merged[0].name != "5, 6 画" {
+		t.Errorf("merged = %v, want \"5, 6 画\"", merged)
+	}
+}
+
+func TestGBCodeRegion(t *testing.T) {
+	c := NewGBCodeIndexCollator(ReadingIndexCollator{})
+	tests := map[rune]int{
+		'啊': CODE_LEVEL1,
+		'座': CODE_LEVEL1,
+		'亍': CODE_LEVEL2,
+		'齄': CODE_LEVEL2,
+		'盄': CODE_EXTENSION, // B140，GBK/4 区
+		'翽': CODE_EXTENSION, // C250
+		'貮': CODE_EXTENSION, // D940
+		'乂': CODE_EXTENSION, // 8156，GBK/3 区
+	}
+	for r, want := range tests {
+		if region := codeRegionOf(c.code(r)); region != want {
+			t.Errorf("region of %c = %d, want %d", r, region, want)
+		}
+	}
+}
+
+func TestOutput_radicalSubheading(t *testing.T) {
+	keys := []string{"林", "本", "木", "机", "李", "江", "河", "汉"}
+	tests := []struct {
+		flag int
+		want string
+	}{
+		{0, "[木部] 木 本 机 李 林 [水部] 汉 江 河"},
+		{1, "[木部] <0 画> 木 <1 画> 本 <2 画> 机 <3 画> 李 <4 画> 林 [水部] <2 画> 汉 <3 画> 江 <5 画> 河"},
+	}
+	for _, test := range tests {
+		style := newTestOutputStyle()
+		style.stroke_subheading_flag = test.flag
+		if got := writeTestIndex("radical", style, keys...); got != test.want {
+			t.Errorf("stroke_subheading_flag %d: output = %q, want %q", test.flag, got, test.want)
+		}
+	}
+}
+
+func TestOutput_firstStroke(t *testing.T) {
+	keys := []string{"人", "丁", "几", "了", "大", "上", "王", "中", "木"}
+	tests := []struct {
+		flag int
+		want string
+	}{
+		{0, "[2 画] 丁 人 几 了 [3 画] 大 上 [4 画] 王 木 中"},
+		{1, "[2 画] <横> 丁 <撇> 人 几 <折> 了 [3 画] <横> 大 <竖> 上 [4 画] <横> 王 木 <竖> 中"},
+		{2, "[2 画] <㇐> 丁 <㇒> 人 几 <㇠> 了 [3 画] <㇐> 大 <㇑> 上 [4 画] <㇐> 王 木 <㇑> 中"},
+	}
+	for _, test := range tests {
+		style := newTestOutputStyle()
+		style.first_stroke_flag = test.flag
+		if got := writeTestIndex("stroke", style, keys...); got != test.want {
+			t.Errorf("first_stroke_flag %d: output = %q, want %q", test.flag, got, test.want)
+		}
+	}
+}
+
+func TestOutput_groupFormat(t *testing.T) {
+	keys := []string{"Apple", "Cat", "Cow", "3", "阿"}
+	tests := []struct {
+		name  string
+		set   func(style *OutputStyle)
+		want  string
+		whole bool // want 是整个输出，否则只比较开头
+	}{
+		{"default", func(style *OutputStyle) {},
+			"[数字] 3 [A] Apple 阿 [C] Cat Cow", true},
+		{"placeholders", func(style *OutputStyle) {
+			style.heading_prefix = " [%{anchor}:%{kind}:%{count}:"
+		}, "[zhmindex.1:numbers:1:数字] 3 [zhmindex.2:letters:2:A] Apple 阿 [zhmindex.4:letters:2:C] Cat Cow", true},
+		{"navbar", func(style *OutputStyle) {
+			style.navbar_prefix, style.navbar_suffix = "<", ">"
+			style.navbar_item, style.navbar_delim = "%{name}=%{anchor}", "|"
+		}, "<数字=zhmindex.1|A=zhmindex.2|C=zhmindex.4> [数字] 3 [A] Apple 阿 [C] Cat Cow", true},
+		{"navbar empty letters", func(style *OutputStyle) {
+			style.navbar_item, style.navbar_empty_item, style.navbar_delim = "%{name}", "(%{name})", "|"
+		}, "数字|A|(B)|C|(D)|(E)|", false},
+		{"empty letter groups", func(style *OutputStyle) {
+			style.empty_group_flag, style.empty_group_placeholder = 1, " -"
+		}, "[数字] 3 [A] Apple 阿 [B] - [C] Cat Cow [D] - [E] -", false},
+		{"all empty groups", func(style *OutputStyle) {
+			style.empty_group_flag, style.empty_group_placeholder = 2, " -"
+		}, "[符号] - [数字] 3 [A] Apple 阿 [B] -", false},
+		{"placeholder without flag", func(style *OutputStyle) {
+			style.empty_group_placeholder = " -"
+		}, "[数字] 3 [A] Apple 阿 [C] Cat Cow", true},
+	}
+	for _, test := range tests {
+		style := newTestOutputStyle()
+		style.numhead_positive, style.symhead_positive = "数字", "符号"
+		test.set(style)
+		got := writeTestIndex("pinyin", style, keys...)
+		if test.whole && got != test.want || !test.whole && !strings.HasPrefix(got, test.want) {
+			t.Errorf("%s: output = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
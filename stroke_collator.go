@@ -1,7 +1,7 @@
 package main
 
 import (
-	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -9,9 +9,11 @@ import (
 )
 
 // 汉字按笔画排序，汉字按笔画分组排在英文字母组后面
-type StrokeIndexCollator struct{}
+type StrokeIndexCollator struct {
+	nested bool // 笔画分组放在 cjk_heading 上层分组之下
+}
 
-func (_ StrokeIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
+func (c StrokeIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
 	// 分组：符号、数字、字母 A..Z、笔划 1..MAX_STROKE
 	groups := make([]IndexGroup, 2+26+CJK.MAX_STROKE)
 	if style.headings_flag > 0 {
@@ -30,27 +32,54 @@ func (_ StrokeIndexCollator) InitGroups(style *OutputStyle) []IndexGroup {
 		}
 	}
 	for stroke, i := 1, 2+26; stroke <= CJK.MAX_STROKE; stroke++ {
-		groups[i].name = style.stroke_prefix + strconv.Itoa(stroke) + style.stroke_suffix
+		groups[i].name = style.stroke_prefix + FormatNumber(stroke, style.heading_number_format) + style.stroke_suffix
+		groups[i].name_prefix, groups[i].name_suffix = style.stroke_prefix, style.stroke_suffix
 		i++
 	}
-	return groups
+	return nestCJKGroups(groups, c.nested, style)
 }
 
 // 取得分组
-func (_ StrokeIndexCollator) Group(entry *IndexEntry) int {
+func (c StrokeIndexCollator) Group(entry *IndexEntry) []int {
 	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
 	first = unicode.ToLower(first)
 	switch {
 	case IsNumString(entry.level[0].key):
-		return 1
+		return c.NumberGroup()
 	case 'a' <= first && first <= 'z':
-		return 2 + int(first) - 'a'
+		return []int{2 + int(first) - 'a'}
 	case len(CJK.Strokes[first]) > 0:
-		return 2 + 26 + (len(CJK.Strokes[first]) - 1)
+		return cjkGroupPath(len(CJK.Strokes[first])-1, c.nested)
 	default:
 		// 符号组
-		return 0
+		return []int{0}
+	}
+}
+
+// 数字分组的路径
+func (_ StrokeIndexCollator) NumberGroup() []int {
+	return []int{1}
+}
+
+// 取得子分组：笔画分组内按首字的第一笔（横、竖、撇、点、折）分组
+// first_stroke_flag 为 1 时子分组名使用笔画名称，为 2 时使用笔画字形
+func (_ StrokeIndexCollator) Subgroup(entry *IndexEntry, style *OutputStyle) string {
+	var names []string
+	switch style.first_stroke_flag {
+	case 0:
+		return ""
+	case 2:
+		names = strings.Fields(style.first_stroke_glyphs)
+	default:
+		names = strings.Fields(style.first_stroke_names)
+	}
+	first, _ := utf8.DecodeRuneInString(entry.level[0].key)
+	strokes := CJK.Strokes[first]
+	// 笔画类型从 1 开始编号，6 表示未知
+	if strokes == "" || int(strokes[0]) > len(names) {
+		return ""
 	}
+	return style.first_stroke_prefix + names[strokes[0]-1] + style.first_stroke_suffix
 }
 
 // 按汉字笔画、笔顺序比较两个字符大小
@@ -19,34 +19,51 @@ import (
 // 这里使用简单 struct 实现，需要大量分情况讨论。
 // 也可以用 map 实现，代码可能会简短并易于扩展，但要动态处理类型
 type InputStyle struct {
-	keyword         string
-	arg_open        rune
-	arg_close       rune
-	actual          rune
-	encap           rune
-	escape          rune
-	level           rune
-	quote           rune
-	page_compositor string
-	range_open      rune
-	range_close     rune
-	comment         rune
+	keyword            string
+	arg_open           rune
+	arg_close          rune
+	actual             rune
+	encap              rune
+	escape             rune
+	level              rune
+	quote              rune
+	page_compositor    string
+	page_compositors   string
+	page_alph_flag     int
+	range_open         rune
+	range_close        rune
+	comment            rune
+	surname_encap      string
+	name_particles     string
+	name_particle_flag int
+	name_mc_flag       int
+	name_surname_first string
+	reading_actual     rune
+	locator_classes    []*LocatorClass
 }
 
 func NewInputStyle() *InputStyle {
 	in := &InputStyle{
-		keyword:         "\\indexentry",
-		arg_open:        '{',
-		arg_close:       '}',
-		actual:          '@',
-		encap:           '|',
-		escape:          '\\',
-		level:           '!',
-		quote:           '"',
-		page_compositor: "-",
-		range_open:      '(',
-		range_close:     ')',
-		comment:         '%',
+		keyword:            "\\indexentry",
+		arg_open:           '{',
+		arg_close:          '}',
+		actual:             '@',
+		encap:              '|',
+		escape:             '\\',
+		level:              '!',
+		quote:              '"',
+		page_compositor:    "-",
+		page_compositors:   "",
+		page_alph_flag:     0,
+		range_open:         '(',
+		range_close:        ')',
+		comment:            '%',
+		surname_encap:      "",
+		name_particles:     "von van der den de del della di da du la le des ten ter zu dos das do",
+		name_particle_flag: PARTICLE_IGNORE,
+		name_mc_flag:       0,
+		name_surname_first: "",
+		reading_actual:     0,
 	}
 	return in
 }
@@ -66,11 +83,72 @@ type OutputStyle struct {
 	numhead_negative          string
 	stroke_prefix             string
 	stroke_suffix             string
+	heading_number_format     string
 	radical_prefix            string
 	radical_suffix            string
 	radical_simplified_flag   int
 	radical_simplified_prefix string
 	radical_simplified_suffix string
+	radical_system            string
+	stroke_subheading_flag    int
+	stroke_subheading_prefix  string
+	stroke_subheading_suffix  string
+	reading_subheading_flag   int
+	reading_subheading_prefix string
+	reading_subheading_suffix string
+	first_stroke_flag         int
+	first_stroke_names        string
+	first_stroke_glyphs       string
+	first_stroke_prefix       string
+	first_stroke_suffix       string
+	subheading_prefix         string
+	subheading_suffix         string
+	subgroup_skip             string
+	subsubheading_prefix      string
+	subsubheading_suffix      string
+	subsubgroup_skip          string
+	cjk_heading               string
+	group_order               string
+	reading_separate_flag     int
+	group_min_size            int
+	group_merge_joiner        string
+	group_merge_list_flag     int
+	empty_group_flag          int
+	empty_group_placeholder   string
+	anchor_prefix             string
+	navbar_prefix             string
+	navbar_item               string
+	navbar_empty_item         string
+	navbar_delim              string
+	navbar_suffix             string
+	symbol_heading_prefix     *string
+	symbol_heading_suffix     *string
+	number_heading_prefix     *string
+	number_heading_suffix     *string
+	letter_heading_prefix     *string
+	letter_heading_suffix     *string
+	cjk_heading_prefix        *string
+	cjk_heading_suffix        *string
+	reading_word_flag         int
+	reading_interleave_flag   int
+	cjk_number_flag           int
+	surname_flag              int
+	code_fallback             string
+	code_level1_heading       string
+	code_level2_heading       string
+	code_extension_heading    string
+	code_fallback_heading     string
+	reading_flag_0            int
+	reading_flag_1            int
+	reading_flag_2            int
+	reading_format            string
+	reading_capital_flag      int
+	reading_prefix            string
+	reading_suffix            string
+	range_abbrev              string
+	range_split_flag          int
+	encap_precedence          []string
+	primary_encap             []string
 	item_0                    string
 	item_1                    string
 	item_2                    string
@@ -112,11 +190,72 @@ func NewOutputStyle() *OutputStyle {
 		numhead_negative:          "numbers",
 		stroke_prefix:             "",
 		stroke_suffix:             " 画",
+		heading_number_format:     "arabic",
 		radical_prefix:            "",
 		radical_suffix:            "部",
 		radical_simplified_flag:   1,
 		radical_simplified_prefix: "（",
 		radical_simplified_suffix: "）",
+		radical_system:            "kangxi",
+		stroke_subheading_flag:    0,
+		stroke_subheading_prefix:  "",
+		stroke_subheading_suffix:  " 画",
+		reading_subheading_flag:   0,
+		reading_subheading_prefix: "",
+		reading_subheading_suffix: "",
+		first_stroke_flag:         0,
+		first_stroke_names:        "横 竖 撇 点 折 其他",
+		first_stroke_glyphs:       "㇐ ㇑ ㇒ ㇔ ㇠ 其他",
+		first_stroke_prefix:       "",
+		first_stroke_suffix:       "",
+		subheading_prefix:         "\n",
+		subheading_suffix:         "",
+		subgroup_skip:             "",
+		subsubheading_prefix:      "\n",
+		subsubheading_suffix:      "",
+		subsubgroup_skip:          "",
+		cjk_heading:               "",
+		group_order:               "symbols numbers letters cjk",
+		reading_separate_flag:     0,
+		group_min_size:            0,
+		group_merge_joiner:        "–",
+		group_merge_list_flag:     0,
+		empty_group_flag:          0,
+		empty_group_placeholder:   "",
+		anchor_prefix:             "zhmindex.",
+		navbar_prefix:             "",
+		navbar_item:               "",
+		navbar_empty_item:         "",
+		navbar_delim:              "",
+		navbar_suffix:             "",
+		symbol_heading_prefix:     nil,
+		symbol_heading_suffix:     nil,
+		number_heading_prefix:     nil,
+		number_heading_suffix:     nil,
+		letter_heading_prefix:     nil,
+		letter_heading_suffix:     nil,
+		cjk_heading_prefix:        nil,
+		cjk_heading_suffix:        nil,
+		reading_word_flag:         0,
+		reading_interleave_flag:   0,
+		cjk_number_flag:           0,
+		surname_flag:              0,
+		code_fallback:             "",
+		code_level1_heading:       "一级汉字",
+		code_level2_heading:       "二级汉字",
+		code_extension_heading:    "扩展汉字",
+		code_fallback_heading:     "其他汉字",
+		reading_flag_0:            0,
+		reading_flag_1:            0,
+		reading_flag_2:            0,
+		reading_format:            "toned",
+		reading_capital_flag:      0,
+		reading_prefix:            " (",
+		reading_suffix:            ")",
+		range_abbrev:              "none",
+		range_split_flag:          0,
+		encap_precedence:          nil,
+		primary_encap:             nil,
 		item_0:          "\n  \\item ",
 		item_1:          "\n    \\subitem ",
 		item_2:          "\n      \\subsubitem ",
@@ -166,11 +305,24 @@ func NewStyles(o *StyleOptions) (*InputStyle, *OutputStyle) {
 	defer styleFile.Close()
 	scanner := bufio.NewScanner(transform.NewReader(styleFile, o.style_decoder))
 	scanner.Split(ScanStyleTokens)
+	last_key := ""
 	for scanner.Scan() {
 		if err := scanner.Err(); err != nil {
 			log.Println(err.Error())
 		}
 		key := scanner.Text()
+		// encap_precedence、primary_encap 后可以有多个串，后面的串接在前面的值之后
+		if strings.ContainsRune("\"`", rune(key[0])) {
+			switch last_key {
+			case "encap_precedence":
+				out.encap_precedence = append(out.encap_precedence, unquote(key))
+				continue
+			case "primary_encap":
+				out.primary_encap = append(out.primary_encap, unquote(key))
+				continue
+			}
+		}
+		last_key = key
 		if !scanner.Scan() {
 			log.Println("格式文件不完整")
 		}
@@ -198,12 +350,30 @@ func NewStyles(o *StyleOptions) (*InputStyle, *OutputStyle) {
 			in.quote = unquoteChar(value)
 		case "page_compositor":
 			in.page_compositor = unquote(value)
+		case "page_compositors":
+			in.page_compositors = unquote(value)
+		case "page_alph_flag":
+			in.page_alph_flag = parseInt(value)
 		case "range_open":
 			in.range_open = unquoteChar(value)
 		case "range_close":
 			in.range_close = unquoteChar(value)
 		case "comment":
 			in.comment = unquoteChar(value)
+		case "surname_encap":
+			in.surname_encap = unquote(value)
+		case "name_particles":
+			in.name_particles = unquote(value)
+		case "name_particle_flag":
+			in.name_particle_flag = parseInt(value)
+		case "name_mc_flag":
+			in.name_mc_flag = parseInt(value)
+		case "name_surname_first":
+			in.name_surname_first = unquote(value)
+		case "reading_actual":
+			in.reading_actual = unquoteChar(value)
+		case "locator_class":
+			in.locator_classes = append(in.locator_classes, parseLocatorClass(unquote(value), len(in.locator_classes)+1))
 		// 输出参数
 		case "preamble":
 			out.preamble = unquote(value)
@@ -233,6 +403,8 @@ func NewStyles(o *StyleOptions) (*InputStyle, *OutputStyle) {
 			out.stroke_prefix = unquote(value)
 		case "stroke_suffix":
 			out.stroke_suffix = unquote(value)
+		case "heading_number_format":
+			out.heading_number_format = unquote(value)
 		case "radical_prefix":
 			out.radical_prefix = unquote(value)
 		case "radical_suffix":
@@ -243,6 +415,126 @@ func NewStyles(o *StyleOptions) (*InputStyle, *OutputStyle) {
 			out.radical_simplified_prefix = unquote(value)
 		case "radical_simplified_suffix":
 			out.radical_simplified_suffix = unquote(value)
+		case "radical_system":
+			out.radical_system = unquote(value)
+		case "stroke_subheading_flag":
+			out.stroke_subheading_flag = parseInt(value)
+		case "stroke_subheading_prefix":
+			out.stroke_subheading_prefix = unquote(value)
+		case "stroke_subheading_suffix":
+			out.stroke_subheading_suffix = unquote(value)
+		case "reading_subheading_flag":
+			out.reading_subheading_flag = parseInt(value)
+		case "reading_subheading_prefix":
+			out.reading_subheading_prefix = unquote(value)
+		case "reading_subheading_suffix":
+			out.reading_subheading_suffix = unquote(value)
+		case "first_stroke_flag":
+			out.first_stroke_flag = parseInt(value)
+		case "first_stroke_names":
+			out.first_stroke_names = unquote(value)
+		case "first_stroke_glyphs":
+			out.first_stroke_glyphs = unquote(value)
+		case "first_stroke_prefix":
+			out.first_stroke_prefix = unquote(value)
+		case "first_stroke_suffix":
+			out.first_stroke_suffix = unquote(value)
+		case "subheading_prefix":
+			out.subheading_prefix = unquote(value)
+		case "subheading_suffix":
+			out.subheading_suffix = unquote(value)
+		case "subgroup_skip":
+			out.subgroup_skip = unquote(value)
+		case "subsubheading_prefix":
+			out.subsubheading_prefix = unquote(value)
+		case "subsubheading_suffix":
+			out.subsubheading_suffix = unquote(value)
+		case "subsubgroup_skip":
+			out.subsubgroup_skip = unquote(value)
+		case "cjk_heading":
+			out.cjk_heading = unquote(value)
+		case "group_order":
+			out.group_order = unquote(value)
+		case "reading_separate_flag":
+			out.reading_separate_flag = parseInt(value)
+		case "group_min_size":
+			out.group_min_size = parseInt(value)
+		case "group_merge_joiner":
+			out.group_merge_joiner = unquote(value)
+		case "group_merge_list_flag":
+			out.group_merge_list_flag = parseInt(value)
+		case "empty_group_flag":
+			out.empty_group_flag = parseInt(value)
+		case "empty_group_placeholder":
+			out.empty_group_placeholder = unquote(value)
+		case "anchor_prefix":
+			out.anchor_prefix = unquote(value)
+		case "navbar_prefix":
+			out.navbar_prefix = unquote(value)
+		case "navbar_item":
+			out.navbar_item = unquote(value)
+		case "navbar_empty_item":
+			out.navbar_empty_item = unquote(value)
+		case "navbar_delim":
+			out.navbar_delim = unquote(value)
+		case "navbar_suffix":
+			out.navbar_suffix = unquote(value)
+		case "symbol_heading_prefix":
+			out.symbol_heading_prefix = unquoteOptional(value)
+		case "symbol_heading_suffix":
+			out.symbol_heading_suffix = unquoteOptional(value)
+		case "number_heading_prefix":
+			out.number_heading_prefix = unquoteOptional(value)
+		case "number_heading_suffix":
+			out.number_heading_suffix = unquoteOptional(value)
+		case "letter_heading_prefix":
+			out.letter_heading_prefix = unquoteOptional(value)
+		case "letter_heading_suffix":
+			out.letter_heading_suffix = unquoteOptional(value)
+		case "cjk_heading_prefix":
+			out.cjk_heading_prefix = unquoteOptional(value)
+		case "cjk_heading_suffix":
+			out.cjk_heading_suffix = unquoteOptional(value)
+		case "reading_word_flag":
+			out.reading_word_flag = parseInt(value)
+		case "reading_interleave_flag":
+			out.reading_interleave_flag = parseInt(value)
+		case "cjk_number_flag":
+			out.cjk_number_flag = parseInt(value)
+		case "surname_flag":
+			out.surname_flag = parseInt(value)
+		case "code_fallback":
+			out.code_fallback = unquote(value)
+		case "code_level1_heading":
+			out.code_level1_heading = unquote(value)
+		case "code_level2_heading":
+			out.code_level2_heading = unquote(value)
+		case "code_extension_heading":
+			out.code_extension_heading = unquote(value)
+		case "code_fallback_heading":
+			out.code_fallback_heading = unquote(value)
+		case "reading_flag_0":
+			out.reading_flag_0 = parseInt(value)
+		case "reading_flag_1":
+			out.reading_flag_1 = parseInt(value)
+		case "reading_flag_2":
+			out.reading_flag_2 = parseInt(value)
+		case "reading_format":
+			out.reading_format = unquote(value)
+		case "reading_capital_flag":
+			out.reading_capital_flag = parseInt(value)
+		case "reading_prefix":
+			out.reading_prefix = unquote(value)
+		case "reading_suffix":
+			out.reading_suffix = unquote(value)
+		case "range_abbrev":
+			out.range_abbrev = unquote(value)
+		case "range_split_flag":
+			out.range_split_flag = parseInt(value)
+		case "encap_precedence":
+			out.encap_precedence = []string{unquote(value)}
+		case "primary_encap":
+			out.primary_encap = []string{unquote(value)}
 		case "item_0":
 			out.item_0 = unquote(value)
 		case "item_1":
@@ -295,6 +587,63 @@ func NewStyles(o *StyleOptions) (*InputStyle, *OutputStyle) {
 	return in, out
 }
 
+// 取得第 depth 层、种类为 kind 的分组名的前缀、后缀与同层分组间的间距
+// 第 0 层为分组，按分组种类使用不同的前后缀，未设置的使用 heading_prefix 与 heading_suffix；
+// 第 1 层为子分组，更深的各层都使用第 2 层的格式
+func (style *OutputStyle) headingFormat(depth, kind int) (prefix, suffix, skip string) {
+	switch depth {
+	case 0:
+		prefix, suffix = style.heading_prefix, style.heading_suffix
+		var kind_prefix, kind_suffix *string
+		switch kind {
+		case GROUP_SYMBOL:
+			kind_prefix, kind_suffix = style.symbol_heading_prefix, style.symbol_heading_suffix
+		case GROUP_NUMBER:
+			kind_prefix, kind_suffix = style.number_heading_prefix, style.number_heading_suffix
+		case GROUP_LETTER:
+			kind_prefix, kind_suffix = style.letter_heading_prefix, style.letter_heading_suffix
+		case GROUP_CJK:
+			kind_prefix, kind_suffix = style.cjk_heading_prefix, style.cjk_heading_suffix
+		}
+		if kind_prefix != nil {
+			prefix = *kind_prefix
+		}
+		if kind_suffix != nil {
+			suffix = *kind_suffix
+		}
+		return prefix, suffix, style.group_skip
+	case 1:
+		return style.subheading_prefix, style.subheading_suffix, style.subgroup_skip
+	default:
+		return style.subsubheading_prefix, style.subsubheading_suffix, style.subsubgroup_skip
+	}
+}
+
+// 取得页码的各个分隔符：page_compositor 在前，然后是 page_compositors 中以空白分隔的各项
+func (in *InputStyle) pageCompositors() []string {
+	compositors := []string{in.page_compositor}
+	for _, compositor := range strings.Fields(in.page_compositors) {
+		if compositor != in.page_compositor {
+			compositors = append(compositors, compositor)
+		}
+	}
+	return compositors
+}
+
+// 取得第 level 级索引项的注音设置 reading_flag_0、reading_flag_1 或 reading_flag_2
+func (style *OutputStyle) readingFlag(level int) int {
+	switch level {
+	case 0:
+		return style.reading_flag_0
+	case 1:
+		return style.reading_flag_1
+	case 2:
+		return style.reading_flag_2
+	default:
+		return 0
+	}
+}
+
 func unquote(src string) string {
 	// 处理双引号中有换行符的串
 	if src[0] == '"' {
@@ -307,6 +656,12 @@ func unquote(src string) string {
 	return dst
 }
 
+// 读入可以不设置的串，nil 表示格式文件中没有设置，与空串不同
+func unquoteOptional(src string) *string {
+	dst := unquote(src)
+	return &dst
+}
+
 func unquoteChar(src string) rune {
 	src = unquote(src)
 	dst, _, tail, err := strconv.UnquoteChar(src, 0)
@@ -38,3 +38,16 @@ func TestScanStyleTokens_comment(t *testing.T) {
 		t.Error(err1, string(tok1), adv1)
 	}
 }
+
+func TestHeadingFormat_kind(t *testing.T) {
+	style := NewOutputStyle()
+	style.heading_prefix = "<"
+	empty := ""
+	style.symbol_heading_prefix = &empty
+	if prefix, _, _ := style.headingFormat(0, GROUP_SYMBOL); prefix != "" {
+		t.Errorf("symbol prefix = %q, want empty", prefix)
+	}
+	if prefix, _, _ := style.headingFormat(0, GROUP_LETTER); prefix != "<" {
+		t.Errorf("letter prefix = %q, want %q", prefix, "<")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/leo-liu/zhmakeindex/CJK"
+)
+
+// 用作姓氏时读音与常用读音不同的字
+var surnameReadings = map[rune]string{
+	'单': "shan4", '仇': "qiu2", '区': "ou1", '解': "xie4", '曾': "zeng1",
+	'朴': "piao2", '查': "zha1", '盖': "ge3", '华': "hua4", '纪': "ji3",
+	'缪': "miao4", '翟': "zhai2", '秘': "bi4", '覃': "qin2", '乐': "yue4",
+	'繁': "po2", '种': "chong2", '员': "yun4", '召': "shao4", '燕': "yan1",
+	'过': "guo1", '句': "gou1", '能': "nai4", '任': "ren2", '宁': "ning4",
+	'费': "fei4", '贾': "jia3", '薄': "bo2", '莘': "shen1", '长': "chang2",
+	'朝': "chao2", '折': "she2", '隗': "wei3", '洗': "xian3", '逄': "pang2",
+	'黑': "he4", '么': "yao1", '乜': "nie4", '炅': "gui4", '郇': "huan2",
+	'褚': "chu3", '都': "du1", '角': "jue2", '祭': "zhai4", '卜': "bu3",
+	'柏': "bai3", '牟': "mou2", '重': "chong2", '蕃': "pi2", '蔚': "yu4",
+	'仉': "zhang3", '鲜': "xian1", '尉': "wei4",
+}
+
+// 复姓及其读音
+var compoundSurnames = map[string][]string{
+	"欧阳": {"ou1", "yang2"}, "司马": {"si1", "ma3"}, "诸葛": {"zhu1", "ge3"},
+	"上官": {"shang4", "guan1"}, "东方": {"dong1", "fang1"}, "皇甫": {"huang2", "fu3"},
+	"尉迟": {"yu4", "chi2"}, "万俟": {"mo4", "qi2"}, "长孙": {"zhang3", "sun1"},
+	"令狐": {"ling2", "hu2"}, "单于": {"chan2", "yu2"}, "澹台": {"tan2", "tai2"},
+	"公孙": {"gong1", "sun1"}, "慕容": {"mu4", "rong2"}, "司徒": {"si1", "tu2"},
+	"司空": {"si1", "kong1"}, "夏侯": {"xia4", "hou2"}, "宇文": {"yu3", "wen2"},
+	"轩辕": {"xuan1", "yuan2"}, "端木": {"duan1", "mu4"}, "百里": {"bai3", "li3"},
+	"呼延": {"hu1", "yan2"}, "南宫": {"nan2", "gong1"}, "西门": {"xi1", "men2"},
+	"独孤": {"du2", "gu1"}, "拓跋": {"tuo4", "ba2"}, "闻人": {"wen2", "ren2"},
+	"公冶": {"gong1", "ye3"}, "太史": {"tai4", "shi3"}, "申屠": {"shen1", "tu2"},
+	"钟离": {"zhong1", "li2"}, "仲孙": {"zhong4", "sun1"}, "濮阳": {"pu2", "yang2"},
+	"淳于": {"chun2", "yu2"}, "赫连": {"he4", "lian2"}, "宗政": {"zong1", "zheng4"},
+	"公羊": {"gong1", "yang2"}, "乐正": {"yue4", "zheng4"}, "第五": {"di4", "wu3"},
+	"亓官": {"qi2", "guan1"}, "羊舌": {"yang2", "she2"},
+}
+
+// 取得人名开头的姓氏读音与姓氏的字数
+// 复姓作为一个整体；不以汉字开头的返回 nil
+func surnameOf(name string) ([]string, int) {
+	token := []rune(name)
+	if len(token) >= 2 {
+		if readings, ok := compoundSurnames[string(token[:2])]; ok {
+			return readings, 2
+		}
+	}
+	if len(token) == 0 {
+		return nil, 0
+	}
+	if reading, ok := surnameReadings[token[0]]; ok {
+		return []string{reading}, 1
+	}
+	if reading := CJK.Readings[token[0]]; reading != "" {
+		return []string{reading}, 1
+	}
+	return nil, 0
+}